@@ -0,0 +1,278 @@
+package sendcloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MAX_MEMBERS_PER_CALL is the number of members SendCloud accepts in one call;
+// larger imports are split automatically.
+const MAX_MEMBERS_PER_CALL = 1000
+
+// AddressListService manages the address lists used with MailReceiver.UseAddressList.
+type AddressListService struct {
+	client *SendCloud
+}
+
+type AddressList struct {
+	Address      string `json:"address"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	MembersCount int    `json:"membersCount"`
+	GmtCreated   string `json:"gmtCreated"`
+	GmtUpdated   string `json:"gmtUpdated"`
+}
+
+type AddressMember struct {
+	Member string                 `json:"member"`
+	Name   string                 `json:"name"`
+	Vars   map[string]interface{} `json:"vars"`
+}
+
+func (m *AddressMember) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Member string          `json:"member"`
+		Name   string          `json:"name"`
+		Vars   json.RawMessage `json:"vars"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	m.Member = raw.Member
+	m.Name = raw.Name
+	m.Vars = nil
+	vars := raw.Vars
+	// Vars are returned either as an object or as a JSON encoded string.
+	var encoded string
+	if err := json.Unmarshal(vars, &encoded); err == nil {
+		vars = json.RawMessage(encoded)
+	}
+	if len(vars) > 0 && vars[0] == '{' {
+		return json.Unmarshal(vars, &m.Vars)
+	}
+	return nil
+}
+
+// ListOptions paginates list calls.
+type ListOptions struct {
+	Start int
+	Limit int
+}
+
+type AddressListList struct {
+	Total        int           `json:"total"`
+	AddressLists []AddressList `json:"dataList"`
+}
+
+type AddressMemberList struct {
+	Total   int             `json:"total"`
+	Members []AddressMember `json:"dataList"`
+}
+
+func (o *ListOptions) prepareListParams() (url.Values, error) {
+	params := url.Values{}
+	if o != nil {
+		if err := validatePage(o.Start, o.Limit); err != nil {
+			return nil, err
+		}
+		setPage(params, o.Start, o.Limit)
+	}
+	return params, nil
+}
+
+// List - List address lists, one page at a time.
+func (s *AddressListService) List(ctx context.Context, opts *ListOptions) (*AddressListList, error) {
+	params, err := opts.prepareListParams()
+	if err != nil {
+		return nil, fmt.Errorf("AddressLists.List: %w", err)
+	}
+	list := new(AddressListList)
+	if err := s.client.call(ctx, addressListListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("AddressLists.List: %w", err)
+	}
+	return list, nil
+}
+
+// Create - Create an address list.
+func (s *AddressListService) Create(ctx context.Context, list *AddressList) error {
+	if err := list.validateAddressList(); err != nil {
+		return fmt.Errorf("AddressLists.Create: %w", err)
+	}
+	if err := s.client.call(ctx, addressListAddPath, list.prepareAddressListParams(), nil, false); err != nil {
+		return fmt.Errorf("AddressLists.Create: %w", err)
+	}
+	return nil
+}
+
+// Update - Update the name and description of the address list with the same address.
+func (s *AddressListService) Update(ctx context.Context, list *AddressList) error {
+	if err := list.validateAddressList(); err != nil {
+		return fmt.Errorf("AddressLists.Update: %w", err)
+	}
+	if err := s.client.call(ctx, addressListUpdatePath, list.prepareAddressListParams(), nil, false); err != nil {
+		return fmt.Errorf("AddressLists.Update: %w", err)
+	}
+	return nil
+}
+
+// Delete - Delete an address list.
+func (s *AddressListService) Delete(ctx context.Context, address string) error {
+	if address == "" {
+		return errors.New("AddressLists.Delete: address cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("address", address)
+	if err := s.client.call(ctx, addressListDeletePath, params, nil, false); err != nil {
+		return fmt.Errorf("AddressLists.Delete: %w", err)
+	}
+	return nil
+}
+
+// ListMembers - List the members of an address list, one page at a time.
+func (s *AddressListService) ListMembers(ctx context.Context, address string, opts *ListOptions) (*AddressMemberList, error) {
+	if address == "" {
+		return nil, errors.New("AddressLists.ListMembers: address cannot be empty")
+	}
+	params, err := opts.prepareListParams()
+	if err != nil {
+		return nil, fmt.Errorf("AddressLists.ListMembers: %w", err)
+	}
+	params.Set("address", address)
+	list := new(AddressMemberList)
+	if err := s.client.call(ctx, addressMemberListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("AddressLists.ListMembers: %w", err)
+	}
+	return list, nil
+}
+
+// AddMembers - Add members to an address list, in chunks of MAX_MEMBERS_PER_CALL.
+// It returns the number of members added before an error occurred.
+func (s *AddressListService) AddMembers(ctx context.Context, address string, members []AddressMember) (int, error) {
+	n, err := s.writeMembers(ctx, addressMemberAddPath, address, members)
+	if err != nil {
+		return n, fmt.Errorf("AddressLists.AddMembers: %w", err)
+	}
+	return n, nil
+}
+
+// UpdateMembers - Update the name and vars of existing members, in chunks of MAX_MEMBERS_PER_CALL.
+// It returns the number of members updated before an error occurred.
+func (s *AddressListService) UpdateMembers(ctx context.Context, address string, members []AddressMember) (int, error) {
+	n, err := s.writeMembers(ctx, addressMemberUpdatePath, address, members)
+	if err != nil {
+		return n, fmt.Errorf("AddressLists.UpdateMembers: %w", err)
+	}
+	return n, nil
+}
+
+// RemoveMembers - Remove members from an address list, in chunks of MAX_MEMBERS_PER_CALL.
+// It returns the number of members removed before an error occurred.
+func (s *AddressListService) RemoveMembers(ctx context.Context, address string, members []string) (int, error) {
+	if address == "" {
+		return 0, errors.New("AddressLists.RemoveMembers: address cannot be empty")
+	}
+	removed := 0
+	for start := 0; start < len(members); start += MAX_MEMBERS_PER_CALL {
+		end := start + MAX_MEMBERS_PER_CALL
+		if end > len(members) {
+			end = len(members)
+		}
+		params := url.Values{}
+		params.Set("address", address)
+		params.Set("members", strings.Join(members[start:end], ";"))
+		if err := s.client.call(ctx, addressMemberDeletePath, params, nil, false); err != nil {
+			return removed, fmt.Errorf("AddressLists.RemoveMembers: %w", err)
+		}
+		removed = end
+	}
+	return removed, nil
+}
+
+func (s *AddressListService) writeMembers(ctx context.Context, path string, address string, members []AddressMember) (int, error) {
+	if address == "" {
+		return 0, errors.New("address cannot be empty")
+	}
+	for i := range members {
+		if err := members[i].validateAddressMember(); err != nil {
+			return 0, err
+		}
+	}
+	written := 0
+	for start := 0; start < len(members); start += MAX_MEMBERS_PER_CALL {
+		end := start + MAX_MEMBERS_PER_CALL
+		if end > len(members) {
+			end = len(members)
+		}
+		params, err := prepareMembersParams(address, members[start:end])
+		if err != nil {
+			return written, err
+		}
+		if err := s.client.call(ctx, path, params, nil, false); err != nil {
+			return written, err
+		}
+		written = end
+	}
+	return written, nil
+}
+
+func prepareMembersParams(address string, members []AddressMember) (url.Values, error) {
+	addresses := make([]string, len(members))
+	names := make([]string, len(members))
+	vars := make([]map[string]interface{}, len(members))
+	hasVars := false
+	for i, member := range members {
+		addresses[i] = member.Member
+		names[i] = member.Name
+		vars[i] = make(map[string]interface{}, len(member.Vars))
+		for key, value := range member.Vars {
+			vars[i][wrapVarKey(key)] = value
+			hasVars = true
+		}
+	}
+	params := url.Values{}
+	params.Set("address", address)
+	params.Set("members", strings.Join(addresses, ";"))
+	params.Set("names", strings.Join(names, ";"))
+	if hasVars {
+		data, err := json.Marshal(vars)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal vars: %v", err)
+		}
+		params.Set("vars", string(data))
+	}
+	return params, nil
+}
+
+func (l *AddressList) validateAddressList() error {
+	switch {
+	case len(l.Address) == 0:
+		return errors.New("address cannot be empty")
+	case len(l.Name) == 0:
+		return errors.New("name cannot be empty")
+	}
+	return nil
+}
+
+func (l *AddressList) prepareAddressListParams() url.Values {
+	params := url.Values{}
+	params.Set("address", l.Address)
+	params.Set("name", l.Name)
+	if l.Description != "" {
+		params.Set("description", l.Description)
+	}
+	return params
+}
+
+func (m *AddressMember) validateAddressMember() error {
+	switch {
+	case len(m.Member) == 0:
+		return errors.New("member cannot be empty")
+	case strings.Contains(m.Member, ";") || strings.Contains(m.Name, ";"):
+		return fmt.Errorf("member [%s] cannot contain ';'", m.Member)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// API user types.
+const (
+	APIUSER_TRIGGER = 0 // transactional email
+	APIUSER_BATCH   = 1 // batch email
+)
+
+// APIUserService manages the API users that send email for a domain.
+type APIUserService struct {
+	client *SendCloud
+}
+
+type APIUser struct {
+	Name        string `json:"name"`
+	APIKey      string `json:"apiKey"` // only returned on creation
+	Type        int    `json:"cType"`  // APIUSER_TRIGGER or APIUSER_BATCH
+	DomainName  string `json:"domainName"`
+	OpenTrack   bool   `json:"openTrack"`
+	ClickTrack  bool   `json:"clickTrack"`
+	Unsubscribe bool   `json:"unsubscribe"`
+}
+
+// APIUserList is a page of API users.
+type APIUserList struct {
+	Total    int       `json:"total"`
+	APIUsers []APIUser `json:"dataList"`
+}
+
+// List - List API users, one page at a time.
+func (s *APIUserService) List(ctx context.Context, opts *ListOptions) (*APIUserList, error) {
+	params, err := opts.prepareListParams()
+	if err != nil {
+		return nil, fmt.Errorf("APIUsers.List: %w", err)
+	}
+	list := new(APIUserList)
+	if err := s.client.call(ctx, apiUserListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("APIUsers.List: %w", err)
+	}
+	return list, nil
+}
+
+// Create - Create an API user bound to a verified domain and return it with its API key.
+func (s *APIUserService) Create(ctx context.Context, user *APIUser) (*APIUser, error) {
+	if err := user.validateAPIUser(); err != nil {
+		return nil, fmt.Errorf("APIUsers.Create: %w", err)
+	}
+	params := url.Values{}
+	params.Set("name", user.Name)
+	params.Set("cType", strconv.Itoa(user.Type))
+	params.Set("domainName", user.DomainName)
+	params.Set("openTrack", strconv.FormatBool(user.OpenTrack))
+	params.Set("clickTrack", strconv.FormatBool(user.ClickTrack))
+	params.Set("unsubscribe", strconv.FormatBool(user.Unsubscribe))
+	var info struct {
+		Data APIUser `json:"data"`
+	}
+	if err := s.client.call(ctx, apiUserAddPath, params, &info, false); err != nil {
+		return nil, fmt.Errorf("APIUsers.Create: %w", err)
+	}
+	return &info.Data, nil
+}
+
+func (u *APIUser) validateAPIUser() error {
+	switch {
+	case len(u.Name) == 0:
+		return errors.New("name cannot be empty")
+	case len(u.DomainName) == 0:
+		return errors.New("domainName cannot be empty")
+	case u.Type != APIUSER_TRIGGER && u.Type != APIUSER_BATCH:
+		return errors.New("cType value is illegal")
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package sendcloud
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+const defaultContentType = "application/octet-stream"
+
+// Attachment is a file attached to an email. The content is read from Reader
+// when the email is sent; the SDK never closes it.
+type Attachment struct {
+	Filename    string
+	ContentType string // detected from Filename or the content when empty
+	Reader      io.Reader
+}
+
+// NewAttachment - Create an attachment whose content is read from r.
+func NewAttachment(filename string, r io.Reader) *Attachment {
+	return &Attachment{
+		Filename: filename,
+		Reader:   r,
+	}
+}
+
+// NewBytesAttachment - Create an attachment from in-memory content.
+func NewBytesAttachment(filename string, data []byte) *Attachment {
+	a := &Attachment{
+		Filename: filename,
+		Reader:   bytes.NewReader(data),
+	}
+	a.ContentType = mime.TypeByExtension(filepath.Ext(filename))
+	if len(a.ContentType) == 0 {
+		a.ContentType = http.DetectContentType(data)
+	}
+	return a
+}
+
+// NewFileAttachment - Create an attachment from an open file, named after the
+// base name of the file. The file is owned by the caller and is not closed.
+func NewFileAttachment(file *os.File) *Attachment {
+	return &Attachment{
+		Filename: filepath.Base(file.Name()),
+		Reader:   file,
+	}
+}
+
+// NewFSAttachment - Create an attachment from a file in fsys, such as an embed.FS.
+func NewFSAttachment(fsys fs.FS, name string) (*Attachment, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return NewBytesAttachment(path.Base(name), data), nil
+}
+
+// DetectContentType - Fill ContentType from the filename extension, falling back
+// to sniffing the first 512 bytes of the content. An explicit ContentType is kept.
+func (a *Attachment) DetectContentType() error {
+	if len(a.ContentType) > 0 {
+		return nil
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(a.Filename)); len(contentType) > 0 {
+		a.ContentType = contentType
+		return nil
+	}
+	head := make([]byte, 512)
+	var n int
+	var err error
+	if rs, ok := a.Reader.(io.ReadSeeker); ok {
+		var offset int64
+		if offset, err = rs.Seek(0, io.SeekCurrent); err != nil {
+			return err
+		}
+		n, err = io.ReadFull(rs, head)
+		if _, seekErr := rs.Seek(offset, io.SeekStart); seekErr != nil {
+			return seekErr
+		}
+	} else {
+		n, err = io.ReadFull(a.Reader, head)
+		a.Reader = io.MultiReader(bytes.NewReader(head[:n]), a.Reader)
+	}
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	a.ContentType = defaultContentType
+	if n > 0 {
+		a.ContentType = http.DetectContentType(head[:n])
+	}
+	return nil
+}
+
+func (a *Attachment) validateAttachment() error {
+	switch {
+	case a == nil:
+		return errors.New("attachment cannot be nil")
+	case len(a.Filename) == 0:
+		return errors.New("attachment filename cannot be empty")
+	case a.Reader == nil:
+		return errors.New("attachment reader cannot be nil")
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package sendcloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const defaultBatchConcurrency = 4
+
+// Recipient is a recipient of a batch send with its substitution variables.
+type Recipient struct {
+	Address string
+	Vars    map[string]interface{}
+}
+
+// BatchOptions controls how the recipients of a batch send are split and sent.
+type BatchOptions struct {
+	BatchSize   int // recipients per send, MAX_RECEIVERS when zero
+	Concurrency int // sends in flight at once, 4 when zero
+}
+
+// BatchOutcome is the outcome of one send of a batch.
+type BatchOutcome struct {
+	Index      int
+	Recipients []string
+	Result     *SendEmailResult
+	Err        error
+}
+
+// BatchResult aggregates the outcomes of a batch send, in batch order.
+type BatchResult struct {
+	Batches []BatchOutcome
+}
+
+// Failed returns the batches that could not be sent.
+func (r *BatchResult) Failed() []BatchOutcome {
+	var failed []BatchOutcome
+	for _, batch := range r.Batches {
+		if batch.Err != nil {
+			failed = append(failed, batch)
+		}
+	}
+	return failed
+}
+
+// BatchError is returned when some batches of a batch send failed.
+// The outcome of every batch is available in the BatchResult.
+type BatchError struct {
+	Failed int
+	Total  int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d batches failed", e.Failed, e.Total)
+}
+
+func (o *BatchOptions) validateBatchOptions() error {
+	switch {
+	case o.BatchSize < 0 || o.BatchSize > MAX_RECEIVERS:
+		return fmt.Errorf("batchSize must be between 1 and %d", MAX_RECEIVERS)
+	case o.Concurrency < 0:
+		return errors.New("concurrency cannot be negative")
+	}
+	return nil
+}
+
+// SendTemplateEmailBatch sends a template email to any number of recipients,
+// split into XSMTPAPI batches of at most MAX_RECEIVERS recipients each. The
+// Pubsub, Filters and Settings of args.Body.Xsmtpapi apply to every batch; its
+// To and Sub must be empty since they are built from recipients. A SendRequestID
+// is suffixed with the batch index.
+func (client *SendCloud) SendTemplateEmailBatch(ctx context.Context, args *TemplateMail, recipients []Recipient, opts *BatchOptions) (*BatchResult, error) {
+	if len(args.Receiver.To) > 0 || args.Receiver.UseAddressList {
+		return nil, errors.New("SendTemplateEmailBatch: receiver cannot be set in a batch send")
+	}
+	if len(args.Body.Xsmtpapi.To) > 0 || len(args.Body.Xsmtpapi.Sub) > 0 {
+		return nil, errors.New("SendTemplateEmailBatch: xsmtpapi to and sub cannot be set in a batch send")
+	}
+	return client.sendBatch(ctx, &args.Body, recipients, opts, func(ctx context.Context, body MailBody) (*SendEmailResult, error) {
+		batch := *args
+		batch.Body = body
+		return client.SendTemplateEmail(ctx, &batch)
+	})
+}
+
+// SendCommonEmailBatch is the common mail equivalent of SendTemplateEmailBatch.
+func (client *SendCloud) SendCommonEmailBatch(ctx context.Context, args *CommonMail, recipients []Recipient, opts *BatchOptions) (*BatchResult, error) {
+	if len(args.Receiver.To) > 0 || args.Receiver.UseAddressList {
+		return nil, errors.New("SendCommonEmailBatch: receiver cannot be set in a batch send")
+	}
+	if len(args.Body.Xsmtpapi.To) > 0 || len(args.Body.Xsmtpapi.Sub) > 0 {
+		return nil, errors.New("SendCommonEmailBatch: xsmtpapi to and sub cannot be set in a batch send")
+	}
+	return client.sendBatch(ctx, &args.Body, recipients, opts, func(ctx context.Context, body MailBody) (*SendEmailResult, error) {
+		batch := *args
+		batch.Body = body
+		return client.SendCommonEmail(ctx, &batch)
+	})
+}
+
+func (client *SendCloud) sendBatch(ctx context.Context, body *MailBody, recipients []Recipient, opts *BatchOptions,
+	send func(ctx context.Context, body MailBody) (*SendEmailResult, error)) (*BatchResult, error) {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	if err := opts.validateBatchOptions(); err != nil {
+		return nil, err
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("recipients cannot be empty")
+	}
+	// The builder of each batch only sees its own recipients.
+	seen := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		address := strings.TrimSpace(recipient.Address)
+		if len(address) > 0 && seen[address] {
+			return nil, fmt.Errorf("recipient [%s] was added twice", address)
+		}
+		seen[address] = true
+	}
+	batchSize := opts.BatchSize
+	if batchSize == 0 {
+		batchSize = MAX_RECEIVERS
+	}
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	// Build every batch up front so that invalid recipients fail before any send.
+	var bodies []MailBody
+	for start := 0; start < len(recipients); start += batchSize {
+		end := start + batchSize
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+		builder := NewXSMTPAPIBuilder()
+		for _, recipient := range recipients[start:end] {
+			builder.AddRecipient(recipient.Address, recipient.Vars)
+		}
+		for key, value := range body.Xsmtpapi.Pubsub {
+			builder.SetPubsub(key, value)
+		}
+		xsmtpapi, err := builder.Build()
+		if err != nil {
+			return nil, fmt.Errorf("batch %d: %w", len(bodies), err)
+		}
+		xsmtpapi.Filters = body.Xsmtpapi.Filters
+		xsmtpapi.Settings = body.Xsmtpapi.Settings
+		batchBody := *body
+		batchBody.Xsmtpapi = xsmtpapi
+		if len(body.SendRequestID) > 0 {
+			batchBody.SendRequestID = fmt.Sprintf("%s-%d", body.SendRequestID, len(bodies))
+		}
+		bodies = append(bodies, batchBody)
+	}
+	if err := shareAttachments(bodies); err != nil {
+		return nil, err
+	}
+
+	result := &BatchResult{Batches: make([]BatchOutcome, len(bodies))}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range bodies {
+		result.Batches[i] = BatchOutcome{Index: i, Recipients: bodies[i].Xsmtpapi.To}
+		select {
+		case <-ctx.Done():
+			result.Batches[i].Err = ctx.Err()
+			continue
+		case semaphore <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(outcome *BatchOutcome, body MailBody) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			outcome.Result, outcome.Err = send(ctx, body)
+		}(&result.Batches[i], bodies[i])
+	}
+	wg.Wait()
+
+	if failed := len(result.Failed()); failed > 0 {
+		return result, &BatchError{Failed: failed, Total: len(result.Batches)}
+	}
+	return result, nil
+}
+
+// shareAttachments gives each batch its own reader over the content of every
+// attachment, since the batches are sent concurrently. Readers that can be read
+// at an offset or rewound are shared through section readers; only the others
+// are read into memory, after checking that they fit in MAX_ATTACHMENT_SIZE.
+func shareAttachments(bodies []MailBody) error {
+	if len(bodies) == 0 || !bodies[0].hasAttachments() {
+		return nil
+	}
+	attachments := bodies[0].Attachments
+	inlineAttachments := bodies[0].InlineAttachments
+	contents := make([]func() io.Reader, 0, len(attachments)+len(inlineAttachments))
+	for _, inline := range inlineAttachments {
+		if err := inline.validateInlineAttachment(); err != nil {
+			return err
+		}
+	}
+	var totalSize int64
+	for _, attachment := range bodies[0].allAttachments() {
+		if err := attachment.validateAttachment(); err != nil {
+			return err
+		}
+		if err := attachment.DetectContentType(); err != nil {
+			return err
+		}
+		content, size, err := shareAttachment(attachment, MAX_ATTACHMENT_SIZE-totalSize)
+		if err != nil {
+			return err
+		}
+		totalSize += size
+		if totalSize > MAX_ATTACHMENT_SIZE {
+			return &AttachmentSizeError{Size: totalSize, Limit: MAX_ATTACHMENT_SIZE}
+		}
+		contents = append(contents, content)
+	}
+	for i := range bodies {
+		if attachments != nil {
+			bodies[i].Attachments = make([]*Attachment, len(attachments))
+			for j, attachment := range attachments {
+				copied := *attachment
+				copied.Reader = contents[j]()
+				bodies[i].Attachments[j] = &copied
+			}
+		}
+		if inlineAttachments == nil {
+			continue
+		}
+		bodies[i].InlineAttachments = make([]*InlineAttachment, len(inlineAttachments))
+		for j, inline := range inlineAttachments {
+			copied := *inline
+			attachment := *inline.Attachment
+			attachment.Reader = contents[len(attachments)+j]()
+			copied.Attachment = &attachment
+			bodies[i].InlineAttachments[j] = &copied
+		}
+	}
+	return nil
+}
+
+// shareAttachment returns a function that creates an independent reader over
+// the remaining content of attachment, and the size of that content. Readers
+// that cannot be rewound are read into memory, failing with an
+// *AttachmentSizeError if they hold more than limit bytes.
+func shareAttachment(attachment *Attachment, limit int64) (func() io.Reader, int64, error) {
+	if seeker, ok := attachment.Reader.(io.Seeker); ok {
+		if offset, size, err := remainingSize(seeker); err == nil {
+			readerAt, ok := attachment.Reader.(io.ReaderAt)
+			if !ok {
+				readerAt = &seekingReaderAt{reader: attachment.Reader.(io.ReadSeeker)}
+			}
+			return func() io.Reader {
+				return io.NewSectionReader(readerAt, offset, size)
+			}, size, nil
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(attachment.Reader, limit+1))
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(len(data)) > limit {
+		return nil, 0, &AttachmentSizeError{Size: MAX_ATTACHMENT_SIZE - limit + int64(len(data)), Limit: MAX_ATTACHMENT_SIZE}
+	}
+	return func() io.Reader {
+		return bytes.NewReader(data)
+	}, int64(len(data)), nil
+}
+
+// remainingSize returns the current offset of seeker and the number of bytes
+// after it, leaving the offset unchanged.
+func remainingSize(seeker io.Seeker) (int64, int64, error) {
+	offset, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, err
+	}
+	return offset, end - offset, nil
+}
+
+// seekingReaderAt implements io.ReaderAt over a reader that can only seek, so
+// that section readers of concurrent batches can share it.
+type seekingReaderAt struct {
+	mu     sync.Mutex
+	reader io.ReadSeeker
+}
+
+func (r *seekingReaderAt) ReadAt(p []byte, offset int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.reader.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(r.reader, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
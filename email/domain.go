@@ -0,0 +1,138 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Domain verification states.
+const (
+	DOMAIN_VERIFY_PENDING = 0
+	DOMAIN_VERIFY_SUCCESS = 1
+)
+
+// DomainService manages the sending domains of the account.
+type DomainService struct {
+	client *SendCloud
+}
+
+// DNSRecord is a DNS record that must be published for a domain to be verified.
+type DNSRecord struct {
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Verify int    `json:"verify"` // DOMAIN_VERIFY_PENDING or DOMAIN_VERIFY_SUCCESS
+}
+
+// Verified reports whether SendCloud found the record.
+func (r DNSRecord) Verified() bool {
+	return r.Verify == DOMAIN_VERIFY_SUCCESS
+}
+
+type Domain struct {
+	Name       string    `json:"name"`
+	Verify     int       `json:"verify"` // DOMAIN_VERIFY_SUCCESS once every record is verified
+	SPF        DNSRecord `json:"spf"`
+	DKIM       DNSRecord `json:"dkim"`
+	MX         DNSRecord `json:"mx"`
+	CNAME      DNSRecord `json:"cname"`
+	GmtCreated string    `json:"gmtCreated"`
+	GmtUpdated string    `json:"gmtUpdated"`
+}
+
+// DNSRecords returns the SPF, DKIM, MX and CNAME records of the domain.
+func (d *Domain) DNSRecords() []DNSRecord {
+	return []DNSRecord{d.SPF, d.DKIM, d.MX, d.CNAME}
+}
+
+// DomainListOptions filters and paginates Domains.List.
+type DomainListOptions struct {
+	Name   string
+	Verify *int // DOMAIN_VERIFY_PENDING or DOMAIN_VERIFY_SUCCESS, all domains when nil
+	Start  int
+	Limit  int
+}
+
+// DomainList is a page of domains.
+type DomainList struct {
+	Total   int      `json:"total"`
+	Domains []Domain `json:"dataList"`
+}
+
+// List - List domains with their DNS records, one page at a time.
+func (s *DomainService) List(ctx context.Context, opts *DomainListOptions) (*DomainList, error) {
+	params := url.Values{}
+	if opts != nil {
+		if err := validatePage(opts.Start, opts.Limit); err != nil {
+			return nil, fmt.Errorf("Domains.List: %w", err)
+		}
+		if opts.Name != "" {
+			params.Set("name", opts.Name)
+		}
+		if opts.Verify != nil {
+			params.Set("verify", strconv.Itoa(*opts.Verify))
+		}
+		setPage(params, opts.Start, opts.Limit)
+	}
+	list := new(DomainList)
+	if err := s.client.call(ctx, domainListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("Domains.List: %w", err)
+	}
+	return list, nil
+}
+
+// Get - Get a domain with the DNS records to publish and their verification status.
+func (s *DomainService) Get(ctx context.Context, name string) (*Domain, error) {
+	if name == "" {
+		return nil, errors.New("Domains.Get: name cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("name", name)
+	list := new(DomainList)
+	if err := s.client.call(ctx, domainListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("Domains.Get: %w", err)
+	}
+	for i := range list.Domains {
+		if strings.EqualFold(list.Domains[i].Name, name) {
+			return &list.Domains[i], nil
+		}
+	}
+	return nil, fmt.Errorf("Domains.Get: domain [%s] does not exist", name)
+}
+
+// Create - Add a sending domain. Publish its DNSRecords to have it verified.
+func (s *DomainService) Create(ctx context.Context, name string) (*Domain, error) {
+	if name == "" {
+		return nil, errors.New("Domains.Create: name cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("name", name)
+	var info struct {
+		Data Domain `json:"data"`
+	}
+	if err := s.client.call(ctx, domainAddPath, params, &info, false); err != nil {
+		return nil, fmt.Errorf("Domains.Create: %w", err)
+	}
+	return &info.Data, nil
+}
+
+// Update - Rename a sending domain.
+func (s *DomainService) Update(ctx context.Context, name string, newName string) error {
+	switch {
+	case name == "":
+		return errors.New("Domains.Update: name cannot be empty")
+	case newName == "":
+		return errors.New("Domains.Update: newName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("newName", newName)
+	if err := s.client.call(ctx, domainUpdatePath, params, nil, false); err != nil {
+		return fmt.Errorf("Domains.Update: %w", err)
+	}
+	return nil
+}
@@ -1,16 +1,18 @@
 package sendcloud
+
 import (
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/httpclient"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
-
-func NewSendCloud(apiUser string, apiKey string) (*SendCloud, error) {
+func NewSendCloud(apiUser string, apiKey string, opts ...Option) (*SendCloud, error) {
 	switch {
 	case len(apiUser) == 0:
 		return nil, errors.New("NewSendCloud: apiUser cannot be empty")
@@ -19,68 +21,118 @@ func NewSendCloud(apiUser string, apiKey string) (*SendCloud, error) {
 	}
 
 	sc := &SendCloud{
-		apiUser: apiUser,
-		apiKey:  apiKey,
-		apiBase: APIBase,
-		client:  http.DefaultClient,
+		apiUser:   apiUser,
+		apiKey:    apiKey,
+		apiBase:   APIBase,
+		client:    http.DefaultClient,
+		userAgent: defaultUserAgent,
+	}
+	sc.Templates = &TemplateService{client: sc}
+	sc.AddressLists = &AddressListService{client: sc}
+	sc.Bounces = newSuppressionService(sc, "Bounces", bouncesPath)
+	sc.Unsubscribes = newSuppressionService(sc, "Unsubscribes", unsubscribesPath)
+	sc.SpamReports = newSuppressionService(sc, "SpamReports", spamReportsPath)
+	sc.InvalidEmails = newSuppressionService(sc, "InvalidEmails", invalidEmailsPath)
+	sc.Labels = &LabelService{client: sc}
+	sc.Domains = &DomainService{client: sc}
+	sc.APIUsers = &APIUserService{client: sc}
+	for _, opt := range opts {
+		if err := opt(sc); err != nil {
+			return nil, fmt.Errorf("NewSendCloud: %w", err)
+		}
 	}
+	httpClient, err := httpclient.Configure(sc.client, sc.timeout, sc.proxy)
+	if err != nil {
+		return nil, fmt.Errorf("NewSendCloud: %w", err)
+	}
+	sc.client = httpClient
 	return sc, nil
 }
 
-func (client *SendCloud) request(ctx context.Context, req *http.Request, responseResult *SendEmailResult) error {
-	req = req.WithContext(ctx)
-	resp, err := client.client.Do(req)
+// managementURL returns the URL of a management endpoint, which lives next to
+// the mail endpoints under the apiv2 root.
+func (client *SendCloud) managementURL(path string) string {
+	return strings.TrimSuffix(client.apiBase, "/mail") + path
+}
+
+// call posts params to a management endpoint and decodes the info of the
+// response into info. Only idempotent calls should set retryable.
+func (client *SendCloud) call(ctx context.Context, path string, params url.Values, info interface{}, retryable bool) error {
+	if err := client.validateConfig(); err != nil {
+		return err
+	}
+	params.Set("apiUser", client.apiUser)
+	params.Set("apiKey", client.apiKey)
+	req, err := http.NewRequest("POST", client.managementURL(path), bytes.NewBufferString(params.Encode()))
 	if err != nil {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
 		return err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.do(ctx, req, "", retryable)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
 
-	err = checkResponse(resp)
+	if apiError := checkResponse(resp); apiError != nil {
+		return apiError
+	}
+	var response apiResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return &APIError{
+			HTTPStatus: resp.StatusCode,
+			StatusCode: response.StatusCode,
+			Message:    response.Message,
+			Endpoint:   req.URL.Path,
+			response:   resp,
+		}
+	}
+	if info != nil && len(response.Info) > 0 && string(response.Info) != "null" {
+		return json.Unmarshal(response.Info, info)
+	}
+	return nil
+}
+
+func (client *SendCloud) request(ctx context.Context, endpoint string, req *http.Request, responseResult *SendEmailResult, sendRequestID string) error {
+	resp, err := client.do(ctx, req, endpoint, len(sendRequestID) > 0)
 	if err != nil {
-		defer resp.Body.Close()
 		return err
 	}
+	defer resp.Body.Close()
+
+	if apiError := checkResponse(resp); apiError != nil {
+		apiError.RequestID = sendRequestID
+		return apiError
+	}
 
 	if responseResult != nil {
 		err = json.NewDecoder(resp.Body).Decode(responseResult)
 		if err != nil {
 			return err
 		}
-		if responseResult.StatusCode!= http.StatusOK {
-			return errors.New(responseResult.Message)
+		if responseResult.StatusCode != http.StatusOK {
+			return &APIError{
+				HTTPStatus: resp.StatusCode,
+				StatusCode: responseResult.StatusCode,
+				Message:    responseResult.Message,
+				Endpoint:   req.URL.Path,
+				RequestID:  sendRequestID,
+				response:   resp,
+			}
 		}
 	}
 	return err
 }
 
-
 func (r *ErrorResponse) Error() string {
 	return fmt.Sprintf("%v %v: %d %v",
 		r.Response.Request.Method, r.Response.Request.URL,
 		r.Response.StatusCode, r.Message)
 }
 
-func checkResponse(r *http.Response) error {
-	if r.StatusCode == http.StatusOK {
-		return nil
-	}
-	errorResponse := &ErrorResponse{Response: r}
-	if r.StatusCode == http.StatusNotFound {
-		errorResponse.Message = "Not Found"
-		return errorResponse
-	}
-	data, err := io.ReadAll(r.Body)
-	if err == nil && data != nil {
-		json.Unmarshal(data, errorResponse)
-	}
-	return errorResponse
-}
-
-
 func (client *SendCloud) SendCommonEmail(ctx context.Context, args *CommonMail) (*SendEmailResult, error) {
 	if err := client.validateConfig(); err != nil {
 		return nil, fmt.Errorf("SendCommonEmail: %w", err)
@@ -88,11 +140,17 @@ func (client *SendCloud) SendCommonEmail(ctx context.Context, args *CommonMail)
 	if err := args.validateCommonEmail(); err != nil {
 		return nil, fmt.Errorf("SendCommonEmail: %w", err)
 	}
+	if err := client.labels.check(ctx, client.Labels, args.Body.LabelName); err != nil {
+		return nil, fmt.Errorf("SendCommonEmail: %w", err)
+	}
+	if err := client.wait(ctx, ENDPOINT_SEND_COMMON); err != nil {
+		return nil, fmt.Errorf("SendCommonEmail: %w", err)
+	}
 	var req *http.Request
 	var err error
 	sendCommonUrl := client.apiBase + sendCommonPath
-	if args.Body.Attachments == nil {
-		params:= client.PrepareSendCommonEmailParams(args)
+	if !args.Body.hasAttachments() {
+		params := client.PrepareSendCommonEmailParams(args)
 		formDataEncoded := params.Encode()
 		req, err = http.NewRequest("POST", sendCommonUrl, bytes.NewBufferString(formDataEncoded))
 		if err != nil {
@@ -100,18 +158,15 @@ func (client *SendCloud) SendCommonEmail(ctx context.Context, args *CommonMail)
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	} else {
-		multipartWriter,payload, err := client.MultipartSendCommonMail(args)
+		req, err = newMultipartRequest(sendCommonUrl, args.Body.allAttachments(), func() (*MultipartBody, error) {
+			return client.MultipartSendCommonMail(args)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("SendCommonEmail: %w", err)
 		}
-		req, err = http.NewRequest("POST", sendCommonUrl, payload)
-		if err != nil {
-			return nil, fmt.Errorf("SendCommonEmail: %w", err)
-		}
-		req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
 	}
 	responseData := new(SendEmailResult)
-	err = client.request(ctx, req, responseData)
+	err = client.request(ctx, ENDPOINT_SEND_COMMON, req, responseData, args.Body.SendRequestID)
 	if err != nil {
 		return responseData, err
 	}
@@ -125,11 +180,17 @@ func (client *SendCloud) SendTemplateEmail(ctx context.Context, args *TemplateMa
 	if err := args.validateTemplateMail(); err != nil {
 		return nil, fmt.Errorf("SendTemplateEmail: %w", err)
 	}
+	if err := client.labels.check(ctx, client.Labels, args.Body.LabelName); err != nil {
+		return nil, fmt.Errorf("SendTemplateEmail: %w", err)
+	}
+	if err := client.wait(ctx, ENDPOINT_SEND_TEMPLATE); err != nil {
+		return nil, fmt.Errorf("SendTemplateEmail: %w", err)
+	}
 	var req *http.Request
 	var err error
-	sendTemplateUrl := client.apiBase+sendTemplatePath
-	if args.Body.Attachments == nil {
-		params:= client.PrepareSendTemplateEmailParams(args)
+	sendTemplateUrl := client.apiBase + sendTemplatePath
+	if !args.Body.hasAttachments() {
+		params := client.PrepareSendTemplateEmailParams(args)
 		formDataEncoded := params.Encode()
 		req, err = http.NewRequest("POST", sendTemplateUrl, bytes.NewBufferString(formDataEncoded))
 		if err != nil {
@@ -137,18 +198,15 @@ func (client *SendCloud) SendTemplateEmail(ctx context.Context, args *TemplateMa
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	} else {
-		multipartWriter,payload, err := client.MultipartSendTemplateEmail(args)
+		req, err = newMultipartRequest(sendTemplateUrl, args.Body.allAttachments(), func() (*MultipartBody, error) {
+			return client.MultipartSendTemplateEmail(args)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("SendTemplateEmail: %w", err)
 		}
-		req, err = http.NewRequest("POST", sendTemplateUrl, payload)
-		if err != nil {
-			return nil, fmt.Errorf("SendTemplateEmail: %w", err)
-		}
-		req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
 	}
 	responseData := new(SendEmailResult)
-	err = client.request(ctx, req, responseData)
+	err = client.request(ctx, ENDPOINT_SEND_TEMPLATE, req, responseData, args.Body.SendRequestID)
 	if err != nil {
 		return responseData, err
 	}
@@ -165,11 +223,17 @@ func (client *SendCloud) SendCalendarMail(ctx context.Context, args *CalendarMai
 	if err := args.Calendar.validateCalendarMail(); err != nil {
 		return nil, fmt.Errorf("SendCalendarMail: %w", err)
 	}
+	if err := client.labels.check(ctx, client.Labels, args.Body.LabelName); err != nil {
+		return nil, fmt.Errorf("SendCalendarMail: %w", err)
+	}
+	if err := client.wait(ctx, ENDPOINT_SEND_CALENDAR); err != nil {
+		return nil, fmt.Errorf("SendCalendarMail: %w", err)
+	}
 	var req *http.Request
 	var err error
 	sendCalendarUrl := client.apiBase + sendCalendarPath
-	if args.Body.Attachments == nil {
-		params:= client.PrepareSendCalendarMailParams(args)
+	if !args.Body.hasAttachments() {
+		params := client.PrepareSendCalendarMailParams(args)
 		formDataEncoded := params.Encode()
 		req, err = http.NewRequest("POST", sendCalendarUrl, bytes.NewBufferString(formDataEncoded))
 		if err != nil {
@@ -177,20 +241,17 @@ func (client *SendCloud) SendCalendarMail(ctx context.Context, args *CalendarMai
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	} else {
-		multipartWriter,payload, err := client.MultipartSendCalendarMail(args)
-		if err != nil {
-			return nil, fmt.Errorf("SendCalendarMail: %w", err)
-		}
-		req, err = http.NewRequest("POST", sendCalendarUrl, payload)
+		req, err = newMultipartRequest(sendCalendarUrl, args.Body.allAttachments(), func() (*MultipartBody, error) {
+			return client.MultipartSendCalendarMail(args)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("SendCalendarMail: %w", err)
 		}
-		req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
 	}
 	responseData := new(SendEmailResult)
-	err = client.request(ctx, req, responseData)
+	err = client.request(ctx, ENDPOINT_SEND_CALENDAR, req, responseData, args.Body.SendRequestID)
 	if err != nil {
 		return responseData, err
 	}
 	return responseData, nil
-}
\ No newline at end of file
+}
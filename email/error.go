@@ -0,0 +1,103 @@
+package sendcloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/apierror"
+	"net/http"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrRateLimited      = apierror.ErrRateLimited
+	ErrInvalidRecipient = apierror.ErrInvalidRecipient
+	ErrAuthFailure      = apierror.ErrAuthFailure
+	ErrTemplateNotFound = apierror.ErrTemplateNotFound
+)
+
+// apiCodes groups SendCloud statusCode values by the sentinel error they map to.
+var apiCodes = apierror.Codes{
+	RateLimited:      []int{40901, 50003},
+	InvalidRecipient: []int{40011, 40012, 40013, 40014, 40016},
+	AuthFailure:      []int{40005, 40006, 40008},
+	TemplateNotFound: []int{40029, 40030},
+}
+
+// APIError is returned when SendCloud rejects a request, either with a non-200
+// HTTP status or with a statusCode other than 200 in the response body.
+type APIError struct {
+	HTTPStatus int    `json:"-"`          // HTTP status of the response
+	StatusCode int    `json:"statusCode"` // SendCloud statusCode from the response body, 0 if absent
+	Message    string `json:"message"`    // SendCloud message from the response body
+	Endpoint   string `json:"-"`          // path of the API endpoint that was called
+	RequestID  string `json:"-"`          // SendRequestID of the send, if any
+
+	response *http.Response
+}
+
+func (e *APIError) Error() string {
+	return apierror.Format(e.Endpoint, e.HTTPStatus, e.StatusCode, e.Message, e.RequestID)
+}
+
+// Is reports whether the error belongs to the category of the given sentinel error.
+func (e *APIError) Is(target error) bool {
+	return apiCodes.Is(target, e.HTTPStatus, e.StatusCode)
+}
+
+// As lets errors.As match the deprecated *ErrorResponse, which failed requests
+// returned before *APIError was introduced.
+func (e *APIError) As(target interface{}) bool {
+	errResp, ok := target.(**ErrorResponse)
+	if !ok {
+		return false
+	}
+	*errResp = &ErrorResponse{Response: e.response, Message: e.Message}
+	return true
+}
+
+// AttachmentSizeError is returned when the total size of the attachments exceeds
+// the limit accepted by SendCloud. It is returned before any upload when the sizes
+// are known; otherwise the upload is aborted as soon as the limit is exceeded, and
+// Size is the number of bytes counted at that point.
+type AttachmentSizeError struct {
+	Size  int64
+	Limit int64
+}
+
+func (e *AttachmentSizeError) Error() string {
+	return fmt.Sprintf("total attachment size %d bytes exceeds the limit of %d bytes", e.Size, e.Limit)
+}
+
+// IsRateLimited reports whether err was caused by SendCloud throttling the request.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsInvalidRecipient reports whether err was caused by an invalid recipient address.
+func IsInvalidRecipient(err error) bool {
+	return errors.Is(err, ErrInvalidRecipient)
+}
+
+// IsAuthFailure reports whether err was caused by invalid apiUser/apiKey credentials.
+func IsAuthFailure(err error) bool {
+	return errors.Is(err, ErrAuthFailure)
+}
+
+// IsTemplateNotFound reports whether err was caused by an unknown template invoke name.
+func IsTemplateNotFound(err error) bool {
+	return errors.Is(err, ErrTemplateNotFound)
+}
+
+func checkResponse(r *http.Response) *APIError {
+	if r.StatusCode == http.StatusOK {
+		return nil
+	}
+	statusCode, message := apierror.Parse(r)
+	return &APIError{
+		HTTPStatus: r.StatusCode,
+		StatusCode: statusCode,
+		Message:    message,
+		Endpoint:   r.Request.URL.Path,
+		response:   r,
+	}
+}
@@ -0,0 +1,102 @@
+package sendcloud
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// InlineAttachment is an image embedded in the email and referenced from the
+// HTML content as <img src="cid:ContentID">.
+type InlineAttachment struct {
+	ContentID string
+	*Attachment
+}
+
+// NewInlineAttachment - Create an inline attachment referenced as cid:contentID.
+// A nil attachment is reported when the email is validated.
+func NewInlineAttachment(contentID string, attachment *Attachment) *InlineAttachment {
+	return &InlineAttachment{
+		ContentID:  contentID,
+		Attachment: attachment,
+	}
+}
+
+func (a *InlineAttachment) validateInlineAttachment() error {
+	if a == nil {
+		return errors.New("inline attachment cannot be nil")
+	}
+	if len(a.ContentID) == 0 {
+		return errors.New("inline attachment contentID cannot be empty")
+	}
+	if strings.ContainsAny(a.ContentID, " ;<>\"") {
+		return fmt.Errorf("inline attachment contentID [%s] contains invalid characters", a.ContentID)
+	}
+	return a.Attachment.validateAttachment()
+}
+
+var localImagePattern = regexp.MustCompile(`(?i)(<img\b[^>]*?\bsrc\s*=\s*)(["'])(file:[^"']+)(["'])`)
+
+// EmbedLocalImages rewrites <img src="file:..."> references in html into cid:
+// references and returns the rewritten html with the images to send inline.
+// Both file:///absolute/path and file:relative/path forms are accepted.
+func EmbedLocalImages(html string) (string, []*InlineAttachment, error) {
+	var inlineAttachments []*InlineAttachment
+	contentIDs := make(map[string]string)
+	var err error
+	rewritten := localImagePattern.ReplaceAllStringFunc(html, func(match string) string {
+		if err != nil {
+			return match
+		}
+		groups := localImagePattern.FindStringSubmatch(match)
+		imagePath, pathErr := localImagePath(groups[3])
+		if pathErr != nil {
+			err = pathErr
+			return match
+		}
+		contentID, ok := contentIDs[imagePath]
+		if !ok {
+			data, readErr := os.ReadFile(imagePath)
+			if readErr != nil {
+				err = readErr
+				return match
+			}
+			contentID = fmt.Sprintf("image%d", len(inlineAttachments)+1)
+			contentIDs[imagePath] = contentID
+			inlineAttachments = append(inlineAttachments,
+				NewInlineAttachment(contentID, NewBytesAttachment(filepath.Base(imagePath), data)))
+		}
+		return groups[1] + groups[2] + "cid:" + contentID + groups[4]
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return rewritten, inlineAttachments, nil
+}
+
+func localImagePath(src string) (string, error) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", err
+	}
+	if len(u.Opaque) > 0 {
+		path, err := url.PathUnescape(u.Opaque)
+		if err != nil {
+			return "", err
+		}
+		return filepath.FromSlash(path), nil
+	}
+	// file://images/logo.png parses with host "images": reading /logo.png instead
+	// would embed an unrelated file.
+	if len(u.Host) > 0 && u.Host != "localhost" {
+		return "", fmt.Errorf("image source [%s] has a host, use file:///absolute/path or file:relative/path", src)
+	}
+	if len(u.Path) == 0 {
+		return "", fmt.Errorf("image source [%s] has no path", src)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
@@ -0,0 +1,171 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/listcache"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrLabelNotFound is returned by sends whose MailBody.LabelName does not exist,
+// when the client was created with WithLabelCheck.
+var ErrLabelNotFound = errors.New("label not found")
+
+// LabelService manages the labels referenced by MailBody.LabelName.
+type LabelService struct {
+	client *SendCloud
+}
+
+type Label struct {
+	LabelID    int    `json:"labelId"`
+	LabelName  string `json:"labelName"`
+	GmtCreated string `json:"gmtCreated"`
+	GmtUpdated string `json:"gmtUpdated"`
+}
+
+// LabelListOptions filters and paginates Labels.List.
+type LabelListOptions struct {
+	Query string // part of the label name
+	Start int
+	Limit int
+}
+
+// LabelList is a page of labels.
+type LabelList struct {
+	Total  int     `json:"total"`
+	Labels []Label `json:"dataList"`
+}
+
+// List - List labels, one page at a time.
+func (s *LabelService) List(ctx context.Context, opts *LabelListOptions) (*LabelList, error) {
+	params := url.Values{}
+	if opts != nil {
+		if err := validatePage(opts.Start, opts.Limit); err != nil {
+			return nil, fmt.Errorf("Labels.List: %w", err)
+		}
+		if opts.Query != "" {
+			params.Set("query", opts.Query)
+		}
+		setPage(params, opts.Start, opts.Limit)
+	}
+	list := new(LabelList)
+	if err := s.client.call(ctx, labelListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("Labels.List: %w", err)
+	}
+	return list, nil
+}
+
+// Get - Get a label by its ID.
+func (s *LabelService) Get(ctx context.Context, labelID int) (*Label, error) {
+	if labelID <= 0 {
+		return nil, errors.New("Labels.Get: labelId must be positive")
+	}
+	params := url.Values{}
+	params.Set("labelId", strconv.Itoa(labelID))
+	var info struct {
+		Data Label `json:"data"`
+	}
+	if err := s.client.call(ctx, labelGetPath, params, &info, true); err != nil {
+		return nil, fmt.Errorf("Labels.Get: %w", err)
+	}
+	return &info.Data, nil
+}
+
+// Create - Create a label and return it with its ID.
+func (s *LabelService) Create(ctx context.Context, labelName string) (*Label, error) {
+	if labelName == "" {
+		return nil, errors.New("Labels.Create: labelName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("labelName", labelName)
+	var info struct {
+		Data Label `json:"data"`
+	}
+	if err := s.client.call(ctx, labelAddPath, params, &info, false); err != nil {
+		return nil, fmt.Errorf("Labels.Create: %w", err)
+	}
+	s.client.labels.invalidate()
+	return &info.Data, nil
+}
+
+// Update - Rename a label.
+func (s *LabelService) Update(ctx context.Context, labelID int, labelName string) error {
+	if labelID <= 0 {
+		return errors.New("Labels.Update: labelId must be positive")
+	}
+	if labelName == "" {
+		return errors.New("Labels.Update: labelName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("labelId", strconv.Itoa(labelID))
+	params.Set("labelName", labelName)
+	if err := s.client.call(ctx, labelUpdatePath, params, nil, false); err != nil {
+		return fmt.Errorf("Labels.Update: %w", err)
+	}
+	s.client.labels.invalidate()
+	return nil
+}
+
+// Delete - Delete a label by its ID.
+func (s *LabelService) Delete(ctx context.Context, labelID int) error {
+	if labelID <= 0 {
+		return errors.New("Labels.Delete: labelId must be positive")
+	}
+	params := url.Values{}
+	params.Set("labelId", strconv.Itoa(labelID))
+	if err := s.client.call(ctx, labelDeletePath, params, nil, false); err != nil {
+		return fmt.Errorf("Labels.Delete: %w", err)
+	}
+	s.client.labels.invalidate()
+	return nil
+}
+
+// labelCache caches the label names of the account for WithLabelCheck.
+// A nil cache disables the check.
+type labelCache struct {
+	names *listcache.Cache
+}
+
+func newLabelCache(ttl time.Duration) *labelCache {
+	return &labelCache{names: listcache.New(ttl)}
+}
+
+// check returns ErrLabelNotFound if labelName is not a label of the account,
+// listing the labels again once the cached list is older than the ttl.
+func (c *labelCache) check(ctx context.Context, s *LabelService, labelName string) error {
+	if c == nil || labelName == "" {
+		return nil
+	}
+	names, err := c.names.Get(ctx, func(ctx context.Context) (interface{}, error) {
+		names := make(map[string]bool)
+		for start := 0; ; start += MAX_PAGE_LIMIT {
+			list, err := s.List(ctx, &LabelListOptions{Start: start, Limit: MAX_PAGE_LIMIT})
+			if err != nil {
+				return nil, err
+			}
+			for _, label := range list.Labels {
+				names[label.LabelName] = true
+			}
+			if len(list.Labels) < MAX_PAGE_LIMIT || start+len(list.Labels) >= list.Total {
+				return names, nil
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if !names.(map[string]bool)[labelName] {
+		return fmt.Errorf("%w: %s", ErrLabelNotFound, labelName)
+	}
+	return nil
+}
+
+func (c *labelCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.names.Invalidate()
+}
@@ -1,7 +1,9 @@
 package sendcloud
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
 	"os"
 	"reflect"
 	"time"
@@ -14,17 +16,87 @@ const (
 	sendCalendarPath = "/sendcalendar"
 )
 
+// Management endpoints, relative to the apiv2 root above APIBase.
+const (
+	templateListPath   = "/template/list"
+	templateGetPath    = "/template/get"
+	templateAddPath    = "/template/add"
+	templateUpdatePath = "/template/update"
+	templateDeletePath = "/template/delete"
+	templateSubmitPath = "/template/submit"
+
+	addressListListPath     = "/addresslist/list"
+	addressListAddPath      = "/addresslist/add"
+	addressListUpdatePath   = "/addresslist/update"
+	addressListDeletePath   = "/addresslist/delete"
+	addressMemberListPath   = "/addressmember/list"
+	addressMemberAddPath    = "/addressmember/add"
+	addressMemberUpdatePath = "/addressmember/update"
+	addressMemberDeletePath = "/addressmember/delete"
+
+	emailStatusPath = "/data/emailStatus"
+	statDayPath     = "/statday/list"
+	statHourPath    = "/stathour/list"
+
+	bouncesPath       = "/bounces"
+	unsubscribesPath  = "/unsubscribes"
+	spamReportsPath   = "/spamreported"
+	invalidEmailsPath = "/invalidemails"
+
+	labelListPath   = "/label/list"
+	labelGetPath    = "/label/get"
+	labelAddPath    = "/label/add"
+	labelUpdatePath = "/label/update"
+	labelDeletePath = "/label/delete"
+
+	domainListPath   = "/domain/list"
+	domainAddPath    = "/domain/add"
+	domainUpdatePath = "/domain/update"
+	apiUserListPath  = "/apiuser/list"
+	apiUserAddPath   = "/apiuser/add"
+
+	accountInfoPath = "/userinfo/get"
+)
+
 type SendCloud struct {
-	apiUser string
-	apiKey  string
-	apiBase string
-	client  *http.Client
+	apiUser   string
+	apiKey    string
+	apiBase   string
+	client    *http.Client
+	timeout   *time.Duration // set by WithTimeout, applied to client by the constructor
+	proxy     *url.URL       // set by WithProxy, applied to client by the constructor
+	userAgent string
+	retry     *RetryPolicy
+	labels    *labelCache
+	limiters  map[string]*RateLimiter
+
+	Templates     *TemplateService
+	AddressLists  *AddressListService
+	Bounces       *SuppressionService
+	Unsubscribes  *SuppressionService
+	SpamReports   *SuppressionService
+	InvalidEmails *SuppressionService
+	Labels        *LabelService
+	Domains       *DomainService
+	APIUsers      *APIUserService
+}
+
+// apiResponse is the envelope of every SendCloud API response.
+type apiResponse struct {
+	Result     bool            `json:"result"`
+	StatusCode int             `json:"statusCode"`
+	Message    string          `json:"message"`
+	Info       json.RawMessage `json:"info"`
 }
 
 type Response struct {
 	*http.Response
 }
 
+// ErrorResponse was the error returned for failed requests.
+//
+// Deprecated: failed requests now return *APIError, which still matches
+// *ErrorResponse through errors.As. Use *APIError in new code.
 type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 	Message  string         `json:"message"` // error message
@@ -57,18 +129,19 @@ type MailReceiver struct {
 }
 
 type MailBody struct {
-	From            string
-	Subject         string
-	ContentSummary  string
-	FromName        string
-	ReplyTo         string
-	LabelName       string
-	Headers         map[string]string
-	Attachments     []*os.File
-	Xsmtpapi        XSMTPAPI
-	SendRequestID   string
-	RespEmailID     bool
-	UseNotification bool
+	From              string
+	Subject           string
+	ContentSummary    string
+	FromName          string
+	ReplyTo           string
+	LabelName         string
+	Headers           map[string]string
+	Attachments       []*Attachment
+	InlineAttachments []*InlineAttachment
+	Xsmtpapi          XSMTPAPI
+	SendRequestID     string
+	RespEmailID       bool
+	UseNotification   bool
 }
 
 type TextContent struct {
@@ -152,9 +225,34 @@ func (e *MailBody) AddHeaders(headers map[string]string) {
 	e.Headers = headers
 }
 
-// AddAttachment - Add an attachment content.
+// AddAttachment - Add an open file as attachment. The file is not closed by the SDK.
 func (e *MailBody) AddAttachment(attachment *os.File) {
-	e.Attachments = append(e.Attachments, attachment)
+	e.Attachments = append(e.Attachments, NewFileAttachment(attachment))
+}
+
+// AddAttachments - Add attachments built from readers, byte slices or file systems.
+func (e *MailBody) AddAttachments(attachments ...*Attachment) {
+	e.Attachments = append(e.Attachments, attachments...)
+}
+
+// AddInlineAttachment - Add an image referenced from the html content as cid:contentID.
+func (e *MailBody) AddInlineAttachment(contentID string, attachment *Attachment) {
+	e.InlineAttachments = append(e.InlineAttachments, NewInlineAttachment(contentID, attachment))
+}
+
+// hasAttachments reports whether the mail has to be sent as a multipart form.
+func (e *MailBody) hasAttachments() bool {
+	return e.Attachments != nil || e.InlineAttachments != nil
+}
+
+// allAttachments returns the regular and inline attachments in the order they are sent.
+func (e *MailBody) allAttachments() []*Attachment {
+	attachments := make([]*Attachment, 0, len(e.Attachments)+len(e.InlineAttachments))
+	attachments = append(attachments, e.Attachments...)
+	for _, inline := range e.InlineAttachments {
+		attachments = append(attachments, inline.Attachment)
+	}
+	return attachments
 }
 
 // SetXsmtpapi - Set the xsmtpapi of the email.
@@ -244,8 +342,39 @@ func (e *MailCalendar) SetValarmTime(valarmTime int) {
 }
 
 type SendEmailResult struct {
-	Result     bool        `json:"result"`
-	StatusCode int         `json:"statusCode"`
-	Message    string      `json:"message"`
-	Info       interface{} `json:"info"`
+	Result     bool            `json:"result"`
+	StatusCode int             `json:"statusCode"`
+	Message    string          `json:"message"`
+	Info       EmailSendInfo   `json:"info"`
+	RawInfo    json.RawMessage `json:"-"` // undecoded info, for fields not covered by Info
+}
+
+// EmailSendInfo is the info returned by a successful send.
+// EmailIDs is only filled when MailBody.RespEmailID is set.
+type EmailSendInfo struct {
+	EmailIDs []string `json:"emailIdList"`
+}
+
+func (r *SendEmailResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Result     bool            `json:"result"`
+		StatusCode int             `json:"statusCode"`
+		Message    string          `json:"message"`
+		Info       json.RawMessage `json:"info"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Result = raw.Result
+	r.StatusCode = raw.StatusCode
+	r.Message = raw.Message
+	r.RawInfo = raw.Info
+	r.Info = EmailSendInfo{}
+	// Failed requests may carry a non-object info, which is left to RawInfo.
+	if len(raw.Info) > 0 && raw.Info[0] == '{' {
+		if err := json.Unmarshal(raw.Info, &r.Info); err != nil {
+			return err
+		}
+	}
+	return nil
 }
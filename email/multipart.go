@@ -0,0 +1,238 @@
+package sendcloud
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// MultipartBody is a multipart form that is streamed to the request through an
+// io.Pipe while attachments are read, instead of being buffered in memory. The
+// form is written from a goroutine that starts on the first Read of Body; closing
+// Body before reading it never starts the goroutine.
+type MultipartBody struct {
+	ContentType   string
+	ContentLength int64 // -1 when the size of an attachment is unknown, sent chunked
+	Body          io.ReadCloser
+	done          chan struct{}
+	err           error // error of the writer, set before done is closed
+}
+
+// streamMultipart sizes the form with a dry run that skips attachment content, then
+// writes it for real from a goroutine into the returned pipe once it is read. write
+// copies the content of each attachment from content, which is nil in the dry run.
+func streamMultipart(attachments []*Attachment, write func(multipartWriter *multipart.Writer, content func(*Attachment) io.Reader) error) (*MultipartBody, error) {
+	for _, attachment := range attachments {
+		if err := attachment.DetectContentType(); err != nil {
+			return nil, err
+		}
+	}
+
+	counter := &countingWriter{}
+	sizer := multipart.NewWriter(counter)
+	if err := write(sizer, nil); err != nil {
+		return nil, err
+	}
+	if err := sizer.Close(); err != nil {
+		return nil, err
+	}
+	contentLength := counter.n
+	var knownSize int64
+	for _, attachment := range attachments {
+		if size := attachmentSize(attachment); size >= 0 {
+			knownSize += size
+		} else {
+			contentLength = -1
+		}
+	}
+	if contentLength >= 0 {
+		contentLength += knownSize
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	multipartWriter := multipart.NewWriter(pipeWriter)
+	if err := multipartWriter.SetBoundary(sizer.Boundary()); err != nil {
+		return nil, err
+	}
+	body := &MultipartBody{
+		ContentType:   multipartWriter.FormDataContentType(),
+		ContentLength: contentLength,
+		done:          make(chan struct{}),
+	}
+	body.Body = &lazyPipeReader{
+		pipeReader: pipeReader,
+		start: func() {
+			go func() {
+				defer close(body.done)
+				limit := &attachmentLimit{size: knownSize}
+				err := write(multipartWriter, limit.reader)
+				if err == nil {
+					err = multipartWriter.Close()
+				}
+				body.err = err
+				pipeWriter.CloseWithError(err)
+			}()
+		},
+		done: body.done,
+	}
+	return body, nil
+}
+
+// lazyPipeReader starts the writer of the pipe on the first Read, so that a body
+// that is dropped without being read does not leave a goroutine behind.
+type lazyPipeReader struct {
+	pipeReader *io.PipeReader
+	start      func()
+	once       sync.Once
+	done       chan struct{}
+}
+
+func (r *lazyPipeReader) Read(p []byte) (int, error) {
+	r.once.Do(r.start)
+	return r.pipeReader.Read(p)
+}
+
+func (r *lazyPipeReader) Close() error {
+	// The writer never runs once the body is closed unread.
+	r.once.Do(func() { close(r.done) })
+	return r.pipeReader.Close()
+}
+
+// newMultipartRequest builds a POST request that streams the form returned by build.
+// When every attachment can be rewound, GetBody streams the form again for retries.
+func newMultipartRequest(url string, attachments []*Attachment, build func() (*MultipartBody, error)) (*http.Request, error) {
+	offsets := attachmentOffsets(attachments)
+	body, err := build()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", url, body.Body)
+	if err != nil {
+		body.Body.Close()
+		return nil, err
+	}
+	req.ContentLength = body.ContentLength
+	req.Header.Set("Content-Type", body.ContentType)
+	if offsets != nil {
+		current := body
+		req.GetBody = func() (io.ReadCloser, error) {
+			// Stop the previous writer before moving the attachment readers.
+			current.Body.Close()
+			<-current.done
+			var sizeErr *AttachmentSizeError
+			if errors.As(current.err, &sizeErr) {
+				return nil, current.err
+			}
+			if err := rewindAttachments(attachments, offsets); err != nil {
+				return nil, err
+			}
+			next, err := build()
+			if err != nil {
+				return nil, err
+			}
+			current = next
+			return next.Body, nil
+		}
+	}
+	return req, nil
+}
+
+// attachmentLimit counts the content of the attachments of unknown size against
+// MAX_ATTACHMENT_SIZE while it is streamed; known sizes are checked before the upload.
+type attachmentLimit struct {
+	size int64 // bytes of attachment content counted so far
+}
+
+// reader returns the reader to copy the content of attachment from.
+func (l *attachmentLimit) reader(attachment *Attachment) io.Reader {
+	if attachmentSize(attachment) >= 0 {
+		return attachment.Reader
+	}
+	return &limitedReader{reader: attachment.Reader, limit: l}
+}
+
+// limitedReader fails with an *AttachmentSizeError once the attachments read
+// through it push the total past MAX_ATTACHMENT_SIZE.
+type limitedReader struct {
+	reader io.Reader
+	limit  *attachmentLimit
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.limit.size += int64(n)
+	if r.limit.size > MAX_ATTACHMENT_SIZE {
+		return n, &AttachmentSizeError{Size: r.limit.size, Limit: MAX_ATTACHMENT_SIZE}
+	}
+	return n, err
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// attachmentSize returns the number of bytes left in the attachment reader, or -1 if unknown.
+func attachmentSize(attachment *Attachment) int64 {
+	switch r := attachment.Reader.(type) {
+	case *bytes.Reader:
+		return int64(r.Len())
+	case *bytes.Buffer:
+		return int64(r.Len())
+	case *strings.Reader:
+		return int64(r.Len())
+	case *io.SectionReader:
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return r.Size() - offset
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// attachmentOffsets records the current offset of every attachment so the body can
+// be streamed again on retry. It returns nil if an attachment cannot be rewound.
+func attachmentOffsets(attachments []*Attachment) []int64 {
+	offsets := make([]int64, len(attachments))
+	for i, attachment := range attachments {
+		seeker, ok := attachment.Reader.(io.Seeker)
+		if !ok {
+			return nil
+		}
+		offset, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil
+		}
+		offsets[i] = offset
+	}
+	return offsets
+}
+
+func rewindAttachments(attachments []*Attachment, offsets []int64) error {
+	for i, attachment := range attachments {
+		if _, err := attachment.Reader.(io.Seeker).Seek(offsets[i], io.SeekStart); err != nil {
+			return err
+		}
+	}
+	return nil
+}
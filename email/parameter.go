@@ -1,12 +1,14 @@
 package sendcloud
 
 import (
-	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"mime/multipart"
+	"net/textproto"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 func (client *SendCloud) PrepareReceiverParams(e *MailReceiver) url.Values {
@@ -175,7 +177,10 @@ func (e *MailReceiver) multipartReceiver(client *SendCloud, multipartWriter *mul
 	return nil
 }
 
-func (e *MailBody) multipartMailBody(multipartWriter *multipart.Writer) error {
+// multipartMailBody writes the mail body fields, copying attachment content from
+// content. Without content only the attachment part headers are written, which is
+// used to size the form.
+func (e *MailBody) multipartMailBody(multipartWriter *multipart.Writer, content func(*Attachment) io.Reader) error {
 
 	var err error
 
@@ -233,18 +238,44 @@ func (e *MailBody) multipartMailBody(multipartWriter *multipart.Writer) error {
 
 	if e.Attachments != nil {
 		for _, attachment := range e.Attachments {
-			defer attachment.Close()
-			partWriter, err = multipartWriter.CreateFormFile("attachments", attachment.Name())
+			partWriter, err = multipartWriter.CreatePart(attachmentHeader("attachments", attachment))
 			if err != nil {
 				return err
 			}
-			_, err = io.Copy(partWriter, attachment)
+			if content == nil {
+				continue
+			}
+			_, err = io.Copy(partWriter, content(attachment))
 			if err != nil {
 				return err
 			}
 		}
 	}
 
+	// Inline images are sent as embeddedImage files, with their content IDs
+	// listed in the same order in embeddedCid.
+	if e.InlineAttachments != nil {
+		contentIDs := make([]string, 0, len(e.InlineAttachments))
+		for _, inline := range e.InlineAttachments {
+			partWriter, err = multipartWriter.CreatePart(attachmentHeader("embeddedImage", inline.Attachment))
+			if err != nil {
+				return err
+			}
+			contentIDs = append(contentIDs, inline.ContentID)
+			if content == nil {
+				continue
+			}
+			_, err = io.Copy(partWriter, content(inline.Attachment))
+			if err != nil {
+				return err
+			}
+		}
+		err = multipartWriter.WriteField("embeddedCid", strings.Join(contentIDs, ";"))
+		if err != nil {
+			return err
+		}
+	}
+
 	if !e.Xsmtpapi.IsEmpty() {
 		xsmtpapi, err := json.Marshal(e.Xsmtpapi)
 		if err != nil {
@@ -282,6 +313,16 @@ func (e *MailBody) multipartMailBody(multipartWriter *multipart.Writer) error {
 	return nil
 }
 
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func attachmentHeader(fieldName string, attachment *Attachment) textproto.MIMEHeader {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(fieldName), quoteEscaper.Replace(attachment.Filename)))
+	header.Set("Content-Type", attachment.ContentType)
+	return header
+}
+
 func (e *MailCalendar) multipartMailCalendar(multipartWriter *multipart.Writer) error {
 	var err error
 
@@ -370,96 +411,91 @@ func (e *MailCalendar) multipartMailCalendar(multipartWriter *multipart.Writer)
 	return nil
 }
 
-func (client *SendCloud) MultipartSendCommonMail(e *CommonMail) (*multipart.Writer, *bytes.Buffer, error) {
-	buf := bytes.Buffer{}
-	multipartWriter := multipart.NewWriter(&buf)
-	var err error
-
-	err = e.Receiver.multipartReceiver(client, multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
-	}
-
-	err = e.Body.multipartMailBody(multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
-	}
-
-	if e.Content.Html != "" {
-		err = multipartWriter.WriteField("html", e.Content.Html)
+func (client *SendCloud) MultipartSendCommonMail(e *CommonMail) (*MultipartBody, error) {
+	return streamMultipart(e.Body.allAttachments(), func(multipartWriter *multipart.Writer, content func(*Attachment) io.Reader) error {
+		err := e.Receiver.multipartReceiver(client, multipartWriter)
 		if err != nil {
-			return multipartWriter, nil, err
+			return err
 		}
-	}
 
-	if e.Content.Plain != "" {
-		err = multipartWriter.WriteField("plain", e.Content.Plain)
+		err = e.Body.multipartMailBody(multipartWriter, content)
 		if err != nil {
-			return multipartWriter, nil, err
+			return err
 		}
-	}
 
-	multipartWriter.Close()
-	return multipartWriter, &buf, nil
-}
-
-func (client *SendCloud) MultipartSendTemplateEmail(e *TemplateMail) (*multipart.Writer, *bytes.Buffer, error) {
-	buf := bytes.Buffer{}
-	multipartWriter := multipart.NewWriter(&buf)
-	var err error
-
-	err = e.Receiver.multipartReceiver(client, multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
-	}
-
-	err = e.Body.multipartMailBody(multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
-	}
+		if e.Content.Html != "" {
+			err = multipartWriter.WriteField("html", e.Content.Html)
+			if err != nil {
+				return err
+			}
+		}
 
-	if e.TemplateInvokeName != "" {
-		err = multipartWriter.WriteField("templateInvokeName", e.TemplateInvokeName)
-		if err != nil {
-			return multipartWriter, nil, err
+		if e.Content.Plain != "" {
+			err = multipartWriter.WriteField("plain", e.Content.Plain)
+			if err != nil {
+				return err
+			}
 		}
-	}
-	multipartWriter.Close()
-	return multipartWriter, &buf, nil
+		return nil
+	})
 }
 
-func (client *SendCloud) MultipartSendCalendarMail(e *CalendarMail) (*multipart.Writer, *bytes.Buffer, error) {
-	buf := bytes.Buffer{}
-	multipartWriter := multipart.NewWriter(&buf)
-	var err error
+func (client *SendCloud) MultipartSendTemplateEmail(e *TemplateMail) (*MultipartBody, error) {
+	return streamMultipart(e.Body.allAttachments(), func(multipartWriter *multipart.Writer, content func(*Attachment) io.Reader) error {
+		err := e.Receiver.multipartReceiver(client, multipartWriter)
+		if err != nil {
+			return err
+		}
 
-	err = e.Receiver.multipartReceiver(client, multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
-	}
+		err = e.Body.multipartMailBody(multipartWriter, content)
+		if err != nil {
+			return err
+		}
 
-	err = e.Body.multipartMailBody(multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
-	}
+		if e.TemplateInvokeName != "" {
+			err = multipartWriter.WriteField("templateInvokeName", e.TemplateInvokeName)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-	if e.Content.Html != "" {
-		err = multipartWriter.WriteField("html", e.Content.Html)
+func (client *SendCloud) MultipartSendCalendarMail(e *CalendarMail) (*MultipartBody, error) {
+	return streamMultipart(e.Body.allAttachments(), func(multipartWriter *multipart.Writer, content func(*Attachment) io.Reader) error {
+		err := e.Receiver.multipartReceiver(client, multipartWriter)
 		if err != nil {
-			return multipartWriter, nil, err
+			return err
 		}
-	}
 
-	if e.Content.Plain != "" {
-		err = multipartWriter.WriteField("plain", e.Content.Plain)
+		err = e.Body.multipartMailBody(multipartWriter, content)
 		if err != nil {
-			return multipartWriter, nil, err
+			return err
 		}
+
+		if e.Content.Html != "" {
+			err = multipartWriter.WriteField("html", e.Content.Html)
+			if err != nil {
+				return err
+			}
+		}
+
+		if e.Content.Plain != "" {
+			err = multipartWriter.WriteField("plain", e.Content.Plain)
+			if err != nil {
+				return err
+			}
+		}
+		return e.Calendar.multipartMailCalendar(multipartWriter)
+	})
+}
+
+func setPage(params url.Values, start int, limit int) {
+	if start > 0 {
+		params.Set("start", strconv.Itoa(start))
 	}
-	err = e.Calendar.multipartMailCalendar(multipartWriter)
-	if err != nil {
-		return multipartWriter, nil, err
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
 	}
-	multipartWriter.Close()
-	return multipartWriter, &buf, nil
 }
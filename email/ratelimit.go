@@ -0,0 +1,40 @@
+package sendcloud
+
+import (
+	"context"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/ratelimit"
+)
+
+// Endpoints that can be rate limited with WithRateLimit.
+const (
+	ENDPOINT_SEND_COMMON   = "send"
+	ENDPOINT_SEND_TEMPLATE = "sendtemplate"
+	ENDPOINT_SEND_CALENDAR = "sendcalendar"
+)
+
+func isValidEndpoint(endpoint string) bool {
+	return endpoint == ENDPOINT_SEND_COMMON ||
+		endpoint == ENDPOINT_SEND_TEMPLATE ||
+		endpoint == ENDPOINT_SEND_CALENDAR
+}
+
+// RateLimiter is a token bucket shared by every goroutine sending through the
+// same client: it allows bursts of up to burst sends and refills at rate sends
+// per second.
+type RateLimiter = ratelimit.Bucket
+
+// RateLimitStats counts the sends that went through a RateLimiter and the time they spent waiting.
+type RateLimitStats = ratelimit.Stats
+
+// RateLimiter returns the limiter of an endpoint set with WithRateLimit, or nil.
+func (client *SendCloud) RateLimiter(endpoint string) *RateLimiter {
+	return client.limiters[endpoint]
+}
+
+// wait blocks until the limiter of endpoint, if any, allows a send.
+func (client *SendCloud) wait(ctx context.Context, endpoint string) error {
+	if limiter := client.limiters[endpoint]; limiter != nil {
+		return limiter.Wait(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package sendcloud
+
+import (
+	"context"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/retry"
+	"net/http"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are retried.
+// Sends are only retried when they carry a SendRequestID, so that SendCloud can
+// drop duplicates of a message that was accepted before the failure surfaced.
+type RetryPolicy = retry.Policy
+
+// DefaultRetryPolicy retries gateway errors and throttling up to three attempts.
+var DefaultRetryPolicy = retry.DefaultPolicy
+
+// do sends the request, retrying transient failures according to the client's
+// retry policy when retryable is true. Retries wait for the rate limiter of
+// endpoint, if any. The caller must close the response body.
+func (client *SendCloud) do(ctx context.Context, req *http.Request, endpoint string, retryable bool) (*http.Response, error) {
+	req.Header.Set("User-Agent", client.userAgent)
+	wait := func(ctx context.Context) error {
+		return client.wait(ctx, endpoint)
+	}
+	return retry.Do(ctx, client.client, req, client.retry, retryable, wait)
+}
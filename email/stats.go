@@ -0,0 +1,156 @@
+package sendcloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/jsonnum"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const statsDateLayout = "2006-01-02"
+
+// StatsQuery filters the statistics returned by GetDailyStats and GetHourlyStats.
+type StatsQuery struct {
+	StartDate time.Time
+	EndDate   time.Time // StartDate when zero
+	APIUsers  []string
+	LabelIDs  []int
+	Domains   []string
+	Aggregate bool // sum all the rows into one
+}
+
+// EmailStats are the email statistics of one day or hour.
+type EmailStats struct {
+	Date          string // yyyy-MM-dd
+	Hour          int    // 0-23, hourly statistics only
+	APIUser       string
+	LabelID       int
+	Domain        string
+	Requests      int
+	Delivered     int
+	InvalidEmails int
+	SoftBounces   int
+	Opens         int
+	UniqueOpens   int
+	Clicks        int
+	UniqueClicks  int
+	Unsubscribes  int
+	SpamReports   int
+}
+
+func (s *EmailStats) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		SendDate         string      `json:"sendDate"`
+		SendHour         jsonnum.Int `json:"sendHour"`
+		APIUser          string      `json:"apiUser"`
+		LabelID          jsonnum.Int `json:"labelId"`
+		Domain           string      `json:"domain"`
+		RequestNum       jsonnum.Int `json:"requestNum"`
+		DeliveredNum     jsonnum.Int `json:"deliveredNum"`
+		InvalidEmailsNum jsonnum.Int `json:"invalidEmailsNum"`
+		BounceNum        jsonnum.Int `json:"bounceNum"`
+		OpenNum          jsonnum.Int `json:"openNum"`
+		UniqueOpensNum   jsonnum.Int `json:"uniqueOpensNum"`
+		ClickNum         jsonnum.Int `json:"clickNum"`
+		UniqueClicksNum  jsonnum.Int `json:"uniqueClicksNum"`
+		UnsubscribeNum   jsonnum.Int `json:"unsubscribeNum"`
+		SpamReportedNum  jsonnum.Int `json:"spamReportedNum"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = EmailStats{
+		Date:          raw.SendDate,
+		Hour:          int(raw.SendHour),
+		APIUser:       raw.APIUser,
+		LabelID:       int(raw.LabelID),
+		Domain:        raw.Domain,
+		Requests:      int(raw.RequestNum),
+		Delivered:     int(raw.DeliveredNum),
+		InvalidEmails: int(raw.InvalidEmailsNum),
+		SoftBounces:   int(raw.BounceNum),
+		Opens:         int(raw.OpenNum),
+		UniqueOpens:   int(raw.UniqueOpensNum),
+		Clicks:        int(raw.ClickNum),
+		UniqueClicks:  int(raw.UniqueClicksNum),
+		Unsubscribes:  int(raw.UnsubscribeNum),
+		SpamReports:   int(raw.SpamReportedNum),
+	}
+	return nil
+}
+
+// GetDailyStats - Get the email statistics of each day in the query range.
+func (client *SendCloud) GetDailyStats(ctx context.Context, query *StatsQuery) ([]EmailStats, error) {
+	stats, err := client.getStats(ctx, statDayPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("GetDailyStats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetHourlyStats - Get the email statistics of each hour in the query range.
+func (client *SendCloud) GetHourlyStats(ctx context.Context, query *StatsQuery) ([]EmailStats, error) {
+	stats, err := client.getStats(ctx, statHourPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("GetHourlyStats: %w", err)
+	}
+	return stats, nil
+}
+
+func (client *SendCloud) getStats(ctx context.Context, path string, query *StatsQuery) ([]EmailStats, error) {
+	if query == nil {
+		return nil, errors.New("query cannot be nil")
+	}
+	if err := query.validateStatsQuery(); err != nil {
+		return nil, err
+	}
+	var info struct {
+		DataList []EmailStats `json:"dataList"`
+	}
+	if err := client.call(ctx, path, query.prepareStatsParams(), &info, true); err != nil {
+		return nil, err
+	}
+	return info.DataList, nil
+}
+
+func (q *StatsQuery) validateStatsQuery() error {
+	switch {
+	case q.StartDate.IsZero():
+		return errors.New("startDate cannot be empty")
+	case !q.EndDate.IsZero() && q.EndDate.Before(q.StartDate):
+		return errors.New("endDate cannot be before startDate")
+	}
+	return nil
+}
+
+func (q *StatsQuery) prepareStatsParams() url.Values {
+	params := url.Values{}
+	params.Set("startDate", q.StartDate.Format(statsDateLayout))
+	endDate := q.EndDate
+	if endDate.IsZero() {
+		endDate = q.StartDate
+	}
+	params.Set("endDate", endDate.Format(statsDateLayout))
+	if len(q.APIUsers) > 0 {
+		params.Set("apiUserList", strings.Join(q.APIUsers, ";"))
+	}
+	if len(q.LabelIDs) > 0 {
+		labelIDs := make([]string, len(q.LabelIDs))
+		for i, labelID := range q.LabelIDs {
+			labelIDs[i] = strconv.Itoa(labelID)
+		}
+		params.Set("labelIdList", strings.Join(labelIDs, ";"))
+	}
+	if len(q.Domains) > 0 {
+		params.Set("domainList", strings.Join(q.Domains, ";"))
+	}
+	if q.Aggregate {
+		params.Set("aggregate", "1")
+	}
+	return params
+}
@@ -0,0 +1,122 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Email statuses and events, as reported by QueryEmailStatus.
+const (
+	EMAIL_STATUS_REQUEST     = "request"
+	EMAIL_STATUS_DELIVER     = "deliver"
+	EMAIL_STATUS_OPEN        = "open"
+	EMAIL_STATUS_CLICK       = "click"
+	EMAIL_STATUS_SOFT_BOUNCE = "soft_bounce"
+	EMAIL_STATUS_INVALID     = "invalid"
+	EMAIL_STATUS_UNSUBSCRIBE = "unsubscribe"
+	EMAIL_STATUS_REPORT_SPAM = "report_spam"
+)
+
+const statusTimeLayout = "2006-01-02 15:04:05"
+
+// EmailStatusQuery filters and paginates QueryEmailStatus. At least one of
+// EmailIDs, SendRequestID, Recipient or the time range must be set.
+type EmailStatusQuery struct {
+	EmailIDs      []string // as returned in SendEmailResult.Info.EmailIDs
+	SendRequestID string
+	Recipient     string
+	StartTime     time.Time
+	EndTime       time.Time
+	Status        string // one of the EMAIL_STATUS_* values, all statuses when empty
+	Start         int
+	Limit         int
+}
+
+// EmailEvent is one event in the life of an email.
+type EmailEvent struct {
+	Event   string `json:"event"`
+	Time    string `json:"time"`
+	Message string `json:"message"`
+	Url     string `json:"url"` // the clicked link of a click event
+}
+
+// EmailStatus is the delivery status of an email to one recipient.
+type EmailStatus struct {
+	EmailID       string       `json:"emailId"`
+	SendRequestID string       `json:"sendRequestId"`
+	APIUser       string       `json:"apiUser"`
+	Recipient     string       `json:"recipient"`
+	LabelID       int          `json:"labelId"`
+	Status        string       `json:"status"`
+	StatusCode    int          `json:"statusCode"`
+	RequestTime   string       `json:"requestTime"`
+	ModifiedTime  string       `json:"modifiedTime"`
+	Events        []EmailEvent `json:"sendLog"`
+}
+
+// EmailStatusList is a page of email statuses.
+type EmailStatusList struct {
+	Total    int           `json:"total"`
+	Statuses []EmailStatus `json:"dataList"`
+}
+
+// QueryEmailStatus - Query the delivery status and events of sent emails, one page at a time.
+func (client *SendCloud) QueryEmailStatus(ctx context.Context, query *EmailStatusQuery) (*EmailStatusList, error) {
+	if query == nil {
+		return nil, errors.New("QueryEmailStatus: query cannot be nil")
+	}
+	if err := query.validateEmailStatusQuery(); err != nil {
+		return nil, fmt.Errorf("QueryEmailStatus: %w", err)
+	}
+	list := new(EmailStatusList)
+	if err := client.call(ctx, emailStatusPath, query.prepareEmailStatusParams(), list, true); err != nil {
+		return nil, fmt.Errorf("QueryEmailStatus: %w", err)
+	}
+	return list, nil
+}
+
+func (q *EmailStatusQuery) validateEmailStatusQuery() error {
+	if err := validatePage(q.Start, q.Limit); err != nil {
+		return err
+	}
+	switch {
+	case len(q.EmailIDs) == 0 && q.SendRequestID == "" && q.Recipient == "" && q.StartTime.IsZero():
+		return errors.New("one of emailIds, sendRequestId, recipient or startTime must be set")
+	case !q.EndTime.IsZero() && q.EndTime.Before(q.StartTime):
+		return errors.New("endTime cannot be before startTime")
+	}
+	switch q.Status {
+	case "", EMAIL_STATUS_REQUEST, EMAIL_STATUS_DELIVER, EMAIL_STATUS_OPEN, EMAIL_STATUS_CLICK,
+		EMAIL_STATUS_SOFT_BOUNCE, EMAIL_STATUS_INVALID, EMAIL_STATUS_UNSUBSCRIBE, EMAIL_STATUS_REPORT_SPAM:
+		return nil
+	}
+	return fmt.Errorf("status [%s] is illegal", q.Status)
+}
+
+func (q *EmailStatusQuery) prepareEmailStatusParams() url.Values {
+	params := url.Values{}
+	if len(q.EmailIDs) > 0 {
+		params.Set("emailIds", strings.Join(q.EmailIDs, ";"))
+	}
+	if q.SendRequestID != "" {
+		params.Set("sendRequestId", q.SendRequestID)
+	}
+	if q.Recipient != "" {
+		params.Set("recipients", q.Recipient)
+	}
+	if !q.StartTime.IsZero() {
+		params.Set("startTime", q.StartTime.Format(statusTimeLayout))
+	}
+	if !q.EndTime.IsZero() {
+		params.Set("endTime", q.EndTime.Format(statusTimeLayout))
+	}
+	if q.Status != "" {
+		params.Set("status", q.Status)
+	}
+	setPage(params, q.Start, q.Limit)
+	return params
+}
@@ -0,0 +1,192 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SuppressionService manages one of the lists of addresses SendCloud no longer
+// sends to: client.Bounces, client.Unsubscribes, client.SpamReports or client.InvalidEmails.
+type SuppressionService struct {
+	client *SendCloud
+	name   string
+	path   string
+}
+
+// Suppression is an address on a suppression list.
+type Suppression struct {
+	Email      string `json:"email"`
+	Reason     string `json:"reason"`
+	LabelID    int    `json:"labelId"`
+	GmtCreated string `json:"gmtCreated"`
+}
+
+// SuppressionListOptions filters and paginates suppression list calls.
+type SuppressionListOptions struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Start     int
+	Limit     int
+}
+
+// SuppressionList is a page of suppressed addresses.
+type SuppressionList struct {
+	Total        int           `json:"total"`
+	Suppressions []Suppression `json:"dataList"`
+}
+
+func newSuppressionService(client *SendCloud, name string, path string) *SuppressionService {
+	return &SuppressionService{client: client, name: name, path: path}
+}
+
+// List - List suppressed addresses, one page at a time.
+func (s *SuppressionService) List(ctx context.Context, opts *SuppressionListOptions) (*SuppressionList, error) {
+	params, err := opts.prepareSuppressionParams()
+	if err != nil {
+		return nil, fmt.Errorf("%s.List: %w", s.name, err)
+	}
+	list, err := s.list(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s.List: %w", s.name, err)
+	}
+	return list, nil
+}
+
+// Query - Get the suppression of an address, or nil if the address is not on the list.
+func (s *SuppressionService) Query(ctx context.Context, email string) (*Suppression, error) {
+	if email == "" {
+		return nil, fmt.Errorf("%s.Query: email cannot be empty", s.name)
+	}
+	params := url.Values{}
+	params.Set("email", email)
+	list, err := s.list(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s.Query: %w", s.name, err)
+	}
+	for i := range list.Suppressions {
+		if strings.EqualFold(list.Suppressions[i].Email, email) {
+			return &list.Suppressions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Delete - Remove addresses from the list, so that SendCloud sends to them again.
+func (s *SuppressionService) Delete(ctx context.Context, emails ...string) error {
+	if len(emails) == 0 {
+		return fmt.Errorf("%s.Delete: emails cannot be empty", s.name)
+	}
+	for _, email := range emails {
+		if email == "" || strings.Contains(email, ";") {
+			return fmt.Errorf("%s.Delete: email [%s] is illegal", s.name, email)
+		}
+	}
+	params := url.Values{}
+	params.Set("email", strings.Join(emails, ";"))
+	if err := s.client.call(ctx, s.path+"/delete", params, nil, false); err != nil {
+		return fmt.Errorf("%s.Delete: %w", s.name, err)
+	}
+	return nil
+}
+
+// Iterate - Iterate over every suppressed address matching opts, fetching pages of
+// opts.Limit addresses (MAX_PAGE_LIMIT when zero) as needed.
+func (s *SuppressionService) Iterate(ctx context.Context, opts *SuppressionListOptions) *SuppressionIterator {
+	it := &SuppressionIterator{ctx: ctx, service: s, index: -1}
+	if opts != nil {
+		it.opts = *opts
+	}
+	if it.opts.Limit == 0 {
+		it.opts.Limit = MAX_PAGE_LIMIT
+	}
+	return it
+}
+
+func (s *SuppressionService) list(ctx context.Context, params url.Values) (*SuppressionList, error) {
+	list := new(SuppressionList)
+	if err := s.client.call(ctx, s.path+"/list", params, list, true); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (o *SuppressionListOptions) prepareSuppressionParams() (url.Values, error) {
+	params := url.Values{}
+	if o == nil {
+		return params, nil
+	}
+	if err := validatePage(o.Start, o.Limit); err != nil {
+		return nil, err
+	}
+	if !o.EndDate.IsZero() && o.EndDate.Before(o.StartDate) {
+		return nil, errors.New("endDate cannot be before startDate")
+	}
+	if !o.StartDate.IsZero() {
+		params.Set("startDate", o.StartDate.Format(statsDateLayout))
+	}
+	if !o.EndDate.IsZero() {
+		params.Set("endDate", o.EndDate.Format(statsDateLayout))
+	}
+	setPage(params, o.Start, o.Limit)
+	return params, nil
+}
+
+// SuppressionIterator iterates over a suppression list:
+//
+//	it := client.Bounces.Iterate(ctx, nil)
+//	for it.Next() {
+//		fmt.Println(it.Suppression().Email)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type SuppressionIterator struct {
+	ctx     context.Context
+	service *SuppressionService
+	opts    SuppressionListOptions
+	page    []Suppression
+	index   int
+	done    bool
+	err     error
+}
+
+// Next advances to the next address, fetching the next page when needed.
+// It returns false at the end of the list or on error.
+func (it *SuppressionIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		return true
+	}
+	if it.done {
+		return false
+	}
+	list, err := it.service.List(it.ctx, &it.opts)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.opts.Start += len(list.Suppressions)
+	if len(list.Suppressions) < it.opts.Limit || it.opts.Start >= list.Total {
+		it.done = true
+	}
+	it.page = list.Suppressions
+	it.index = 0
+	return len(it.page) > 0
+}
+
+// Suppression returns the current address.
+func (it *SuppressionIterator) Suppression() Suppression {
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SuppressionIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,173 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Template types.
+const (
+	TEMPLATE_TRIGGER = 0 // transactional email
+	TEMPLATE_BATCH   = 1 // batch email
+)
+
+// Template audit states.
+const (
+	TEMPLATE_STAT_NOT_SUBMITTED = -2
+	TEMPLATE_STAT_REJECTED      = -1
+	TEMPLATE_STAT_PENDING       = 0
+	TEMPLATE_STAT_APPROVED      = 1
+)
+
+// TemplateService manages email templates, invoked by TemplateMail.TemplateInvokeName.
+type TemplateService struct {
+	client *SendCloud
+}
+
+type Template struct {
+	InvokeName   string `json:"invokeName"`
+	Name         string `json:"name"`
+	Subject      string `json:"subject"`
+	Html         string `json:"html"`
+	Plain        string `json:"plain"`
+	TemplateType int    `json:"templateType"`
+	TemplateStat int    `json:"templateStat"`
+	GmtCreated   string `json:"gmtCreated"`
+	GmtUpdated   string `json:"gmtUpdated"`
+}
+
+// TemplateListOptions filters and paginates Templates.List.
+type TemplateListOptions struct {
+	InvokeName   string
+	TemplateType *int // TEMPLATE_TRIGGER or TEMPLATE_BATCH, all types when nil
+	Start        int
+	Limit        int
+}
+
+// TemplateList is a page of templates.
+type TemplateList struct {
+	Total     int        `json:"total"`
+	Templates []Template `json:"dataList"`
+}
+
+// List - List templates, one page at a time.
+func (s *TemplateService) List(ctx context.Context, opts *TemplateListOptions) (*TemplateList, error) {
+	params := url.Values{}
+	if opts != nil {
+		if err := validatePage(opts.Start, opts.Limit); err != nil {
+			return nil, fmt.Errorf("Templates.List: %w", err)
+		}
+		if opts.InvokeName != "" {
+			params.Set("invokeName", opts.InvokeName)
+		}
+		if opts.TemplateType != nil {
+			params.Set("templateType", strconv.Itoa(*opts.TemplateType))
+		}
+		setPage(params, opts.Start, opts.Limit)
+	}
+	list := new(TemplateList)
+	if err := s.client.call(ctx, templateListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("Templates.List: %w", err)
+	}
+	return list, nil
+}
+
+// Get - Get a template by its invoke name.
+func (s *TemplateService) Get(ctx context.Context, invokeName string) (*Template, error) {
+	if invokeName == "" {
+		return nil, errors.New("Templates.Get: invokeName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("invokeName", invokeName)
+	var info struct {
+		Data Template `json:"data"`
+	}
+	if err := s.client.call(ctx, templateGetPath, params, &info, true); err != nil {
+		return nil, fmt.Errorf("Templates.Get: %w", err)
+	}
+	return &info.Data, nil
+}
+
+// Create - Create a template. Set submit to send it for review right away.
+func (s *TemplateService) Create(ctx context.Context, template *Template, submit bool) error {
+	if err := template.validateTemplate(); err != nil {
+		return fmt.Errorf("Templates.Create: %w", err)
+	}
+	params := template.prepareTemplateParams()
+	params.Set("isSubmitAudit", strconv.FormatBool(submit))
+	if err := s.client.call(ctx, templateAddPath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Create: %w", err)
+	}
+	return nil
+}
+
+// Update - Update the template with the same invoke name. Set submit to send it for review right away.
+func (s *TemplateService) Update(ctx context.Context, template *Template, submit bool) error {
+	if err := template.validateTemplate(); err != nil {
+		return fmt.Errorf("Templates.Update: %w", err)
+	}
+	params := template.prepareTemplateParams()
+	params.Set("isSubmitAudit", strconv.FormatBool(submit))
+	if err := s.client.call(ctx, templateUpdatePath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Update: %w", err)
+	}
+	return nil
+}
+
+// Delete - Delete a template by its invoke name.
+func (s *TemplateService) Delete(ctx context.Context, invokeName string) error {
+	if invokeName == "" {
+		return errors.New("Templates.Delete: invokeName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("invokeName", invokeName)
+	if err := s.client.call(ctx, templateDeletePath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Delete: %w", err)
+	}
+	return nil
+}
+
+// Submit - Submit a template for review.
+func (s *TemplateService) Submit(ctx context.Context, invokeName string) error {
+	if invokeName == "" {
+		return errors.New("Templates.Submit: invokeName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("invokeName", invokeName)
+	if err := s.client.call(ctx, templateSubmitPath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Submit: %w", err)
+	}
+	return nil
+}
+
+func (t *Template) validateTemplate() error {
+	switch {
+	case len(t.InvokeName) == 0:
+		return errors.New("invokeName cannot be empty")
+	case len(t.Name) == 0:
+		return errors.New("name cannot be empty")
+	case len(t.Subject) == 0:
+		return errors.New("subject cannot be empty")
+	case len(t.Html) == 0:
+		return errors.New("html cannot be empty")
+	case t.TemplateType != TEMPLATE_TRIGGER && t.TemplateType != TEMPLATE_BATCH:
+		return errors.New("templateType value is illegal")
+	}
+	return nil
+}
+
+func (t *Template) prepareTemplateParams() url.Values {
+	params := url.Values{}
+	params.Set("invokeName", t.InvokeName)
+	params.Set("name", t.Name)
+	params.Set("subject", t.Subject)
+	params.Set("html", t.Html)
+	if t.Plain != "" {
+		params.Set("plain", t.Plain)
+	}
+	params.Set("templateType", strconv.Itoa(t.TemplateType))
+	return params
+}
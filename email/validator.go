@@ -3,16 +3,25 @@ package sendcloud
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
 const MAX_RECEIVERS = 100
 const MAX_MAILLIST = 5
+const MAX_ATTACHMENT_SIZE = 10 * 1024 * 1024
+const MAX_PAGE_LIMIT = 100
 
 func (client *SendCloud) validateConfig() error {
 	if len(client.apiBase) == 0 {
 		client.apiBase = APIBase
 	}
+	if client.client == nil {
+		client.client = http.DefaultClient
+	}
+	if len(client.userAgent) == 0 {
+		client.userAgent = defaultUserAgent
+	}
 	switch {
 	case len(client.apiUser) == 0:
 		return errors.New("apiUser cannot be empty")
@@ -163,6 +172,26 @@ func (e *MailBody) validateMailBody() error {
 	case len(e.Subject) == 0:
 		return errors.New("subject cannot be empty")
 	}
+	for _, attachment := range e.Attachments {
+		if err := attachment.validateAttachment(); err != nil {
+			return err
+		}
+	}
+	for _, inline := range e.InlineAttachments {
+		if err := inline.validateInlineAttachment(); err != nil {
+			return err
+		}
+	}
+	var attachmentsSize int64
+	for _, attachment := range e.allAttachments() {
+		if size := attachmentSize(attachment); size > 0 {
+			attachmentsSize += size
+		}
+	}
+	// Attachments of unknown size are counted while they are streamed.
+	if attachmentsSize > MAX_ATTACHMENT_SIZE {
+		return &AttachmentSizeError{Size: attachmentsSize, Limit: MAX_ATTACHMENT_SIZE}
+	}
 	return nil
 }
 
@@ -209,3 +238,13 @@ func (f Filter) ValidateFilter() error {
 	}
 	return nil
 }
+
+func validatePage(start int, limit int) error {
+	switch {
+	case start < 0:
+		return errors.New("start cannot be negative")
+	case limit < 0 || limit > MAX_PAGE_LIMIT:
+		return fmt.Errorf("limit must be between 0 and %d (0 for the default)", MAX_PAGE_LIMIT)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Event types posted by SendCloud.
+const (
+	EVENT_DELIVER     = "deliver"
+	EVENT_OPEN        = "open"
+	EVENT_CLICK       = "click"
+	EVENT_SOFT_BOUNCE = "soft_bounce"
+	EVENT_INVALID     = "invalid"
+	EVENT_UNSUBSCRIBE = "unsubscribe"
+	EVENT_REPORT_SPAM = "report_spam"
+)
+
+// Event holds the fields common to every event.
+type Event struct {
+	Type      string
+	EmailID   string
+	Recipient string
+	LabelID   int
+	Message   string
+	Timestamp time.Time
+	Form      url.Values // every field posted by SendCloud
+}
+
+// DeliverEvent is posted when an email was accepted by the recipient's server.
+type DeliverEvent struct {
+	Event
+}
+
+// OpenEvent is posted when the recipient opened an email.
+type OpenEvent struct {
+	Event
+	IP        string
+	UserAgent string
+}
+
+// ClickEvent is posted when the recipient clicked a tracked link.
+type ClickEvent struct {
+	Event
+	URL       string
+	IP        string
+	UserAgent string
+}
+
+// BounceEvent is posted when an email bounced, either temporarily
+// (EVENT_SOFT_BOUNCE) or because the address is invalid (EVENT_INVALID).
+type BounceEvent struct {
+	Event
+	SubStat     int
+	SubStatDesc string
+}
+
+// Soft reports whether the bounce is temporary.
+func (e *BounceEvent) Soft() bool {
+	return e.Type == EVENT_SOFT_BOUNCE
+}
+
+// UnsubscribeEvent is posted when the recipient unsubscribed.
+type UnsubscribeEvent struct {
+	Event
+}
+
+// SpamEvent is posted when the recipient reported an email as spam.
+type SpamEvent struct {
+	Event
+}
+
+func newEvent(form url.Values, timestamp time.Time) Event {
+	labelID, _ := strconv.Atoi(form.Get("labelId"))
+	return Event{
+		Type:      form.Get("event"),
+		EmailID:   form.Get("emailId"),
+		Recipient: form.Get("recipient"),
+		LabelID:   labelID,
+		Message:   form.Get("message"),
+		Timestamp: timestamp,
+		Form:      form,
+	}
+}
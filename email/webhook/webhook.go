@@ -0,0 +1,199 @@
+// Package webhook receives the email events SendCloud posts to a webhook URL.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultMaxAge is how old an event may be before it is rejected as stale.
+const DefaultMaxAge = 5 * time.Minute
+
+// Option configures a Handler created by NewHandler.
+type Option func(*Handler) error
+
+// WithMaxAge - Set how old an event may be before it is rejected as stale.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(h *Handler) error {
+		if maxAge <= 0 {
+			return errors.New("WithMaxAge: maxAge must be positive")
+		}
+		h.maxAge = maxAge
+		return nil
+	}
+}
+
+// Handler is an http.Handler that verifies and dispatches SendCloud email events.
+// Register the callbacks before serving requests. An event type without a
+// callback is acknowledged and dropped. A callback returning an error makes the
+// handler answer 500, so that SendCloud posts the event again later.
+type Handler struct {
+	appKey string
+	maxAge time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]time.Time // tokens seen within maxAge, to reject replays
+
+	onDeliver     func(*DeliverEvent) error
+	onOpen        func(*OpenEvent) error
+	onClick       func(*ClickEvent) error
+	onBounce      func(*BounceEvent) error
+	onUnsubscribe func(*UnsubscribeEvent) error
+	onSpam        func(*SpamEvent) error
+}
+
+// NewHandler - Create a Handler verifying events with the app key of the webhook.
+func NewHandler(appKey string, opts ...Option) (*Handler, error) {
+	if len(appKey) == 0 {
+		return nil, errors.New("NewHandler: appKey cannot be empty")
+	}
+	h := &Handler{
+		appKey: appKey,
+		maxAge: DefaultMaxAge,
+		tokens: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		if err := opt(h); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// OnDeliver - Set the callback of deliver events.
+func (h *Handler) OnDeliver(fn func(*DeliverEvent) error) { h.onDeliver = fn }
+
+// OnOpen - Set the callback of open events.
+func (h *Handler) OnOpen(fn func(*OpenEvent) error) { h.onOpen = fn }
+
+// OnClick - Set the callback of click events.
+func (h *Handler) OnClick(fn func(*ClickEvent) error) { h.onClick = fn }
+
+// OnBounce - Set the callback of soft bounce and invalid address events.
+func (h *Handler) OnBounce(fn func(*BounceEvent) error) { h.onBounce = fn }
+
+// OnUnsubscribe - Set the callback of unsubscribe events.
+func (h *Handler) OnUnsubscribe(fn func(*UnsubscribeEvent) error) { h.onUnsubscribe = fn }
+
+// OnSpam - Set the callback of spam report events.
+func (h *Handler) OnSpam(fn func(*SpamEvent) error) { h.onSpam = fn }
+
+// Verify reports whether signature is the hex encoded HMAC-SHA256 of
+// timestamp+token keyed with the app key.
+func Verify(appKey string, timestamp string, token string, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(appKey))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	form := r.PostForm
+	timestamp, token := form.Get("timestamp"), form.Get("token")
+	if len(token) == 0 || !Verify(h.appKey, timestamp, token, form.Get("signature")) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	sentAt, err := parseTimestamp(timestamp)
+	if err != nil {
+		http.Error(w, "invalid timestamp", http.StatusBadRequest)
+		return
+	}
+	if age := time.Now().Sub(sentAt); age > h.maxAge || age < -h.maxAge {
+		http.Error(w, "stale event", http.StatusForbidden)
+		return
+	}
+	if !h.remember(token) {
+		http.Error(w, "replayed event", http.StatusForbidden)
+		return
+	}
+	if err := h.dispatch(newEvent(form, sentAt)); err != nil {
+		h.forget(token)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+func (h *Handler) dispatch(event Event) error {
+	form := event.Form
+	switch event.Type {
+	case EVENT_DELIVER:
+		if h.onDeliver != nil {
+			return h.onDeliver(&DeliverEvent{Event: event})
+		}
+	case EVENT_OPEN:
+		if h.onOpen != nil {
+			return h.onOpen(&OpenEvent{Event: event, IP: form.Get("ip"), UserAgent: form.Get("userAgent")})
+		}
+	case EVENT_CLICK:
+		if h.onClick != nil {
+			return h.onClick(&ClickEvent{Event: event, URL: form.Get("url"), IP: form.Get("ip"), UserAgent: form.Get("userAgent")})
+		}
+	case EVENT_SOFT_BOUNCE, EVENT_INVALID:
+		if h.onBounce != nil {
+			subStat, _ := strconv.Atoi(form.Get("subStat"))
+			return h.onBounce(&BounceEvent{Event: event, SubStat: subStat, SubStatDesc: form.Get("subStatDesc")})
+		}
+	case EVENT_UNSUBSCRIBE:
+		if h.onUnsubscribe != nil {
+			return h.onUnsubscribe(&UnsubscribeEvent{Event: event})
+		}
+	case EVENT_REPORT_SPAM:
+		if h.onSpam != nil {
+			return h.onSpam(&SpamEvent{Event: event})
+		}
+	}
+	return nil
+}
+
+// remember records a token and reports whether it was not seen before.
+// Tokens older than maxAge are dropped, since their events are stale anyway.
+func (h *Handler) remember(token string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	for seen, at := range h.tokens {
+		if now.Sub(at) > 2*h.maxAge {
+			delete(h.tokens, seen)
+		}
+	}
+	if _, ok := h.tokens[token]; ok {
+		return false
+	}
+	h.tokens[token] = now
+	return true
+}
+
+// forget drops a token whose event could not be handled, so that it can be posted again.
+func (h *Handler) forget(token string) {
+	h.mu.Lock()
+	delete(h.tokens, token)
+	h.mu.Unlock()
+}
+
+// parseTimestamp parses a Unix timestamp in seconds or milliseconds.
+func parseTimestamp(timestamp string) (time.Time, error) {
+	n, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if n > 1e12 {
+		return time.Unix(0, n*int64(time.Millisecond)), nil
+	}
+	return time.Unix(n, 0), nil
+}
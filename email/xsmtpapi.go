@@ -0,0 +1,141 @@
+package sendcloud
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// XSMTPAPIBuilder builds an XSMTPAPI one recipient at a time, keeping every
+// substitution list aligned with the recipient list.
+type XSMTPAPIBuilder struct {
+	to       []string
+	index    map[string]bool
+	sub      map[string][]interface{}
+	pubsub   map[string]interface{}
+	filters  *Filter
+	settings *Settings
+	err      error
+}
+
+// NewXSMTPAPIBuilder - Create an empty XSMTPAPI builder.
+func NewXSMTPAPIBuilder() *XSMTPAPIBuilder {
+	return &XSMTPAPIBuilder{
+		index:  make(map[string]bool),
+		sub:    make(map[string][]interface{}),
+		pubsub: make(map[string]interface{}),
+	}
+}
+
+// wrapVarKey returns key in the '%key%' form expected by SendCloud.
+func wrapVarKey(key string) string {
+	if len(key) >= 2 && key[0] == '%' && key[len(key)-1] == '%' {
+		return key
+	}
+	return "%" + key + "%"
+}
+
+// AddRecipient - Add a recipient with its substitution variables. Keys are wrapped
+// as '%key%' when needed; variables missing for a recipient are sent as "".
+func (b *XSMTPAPIBuilder) AddRecipient(address string, vars map[string]interface{}) *XSMTPAPIBuilder {
+	if b.err != nil {
+		return b
+	}
+	address = strings.TrimSpace(address)
+	switch {
+	case len(address) == 0:
+		b.err = errors.New("recipient address cannot be empty")
+		return b
+	case b.index[address]:
+		b.err = fmt.Errorf("recipient [%s] was added twice", address)
+		return b
+	}
+	row := len(b.to)
+	b.to = append(b.to, address)
+	b.index[address] = true
+	for key, value := range vars {
+		key = wrapVarKey(key)
+		values, ok := b.sub[key]
+		if len(values) > row {
+			b.err = fmt.Errorf("variable [%s] was given twice for recipient [%s]", key, address)
+			return b
+		}
+		if !ok {
+			values = make([]interface{}, row, row+1)
+			for i := range values {
+				values[i] = ""
+			}
+		}
+		b.sub[key] = append(values, value)
+	}
+	for key, values := range b.sub {
+		if len(values) == row {
+			b.sub[key] = append(values, "")
+		}
+	}
+	return b
+}
+
+// SetPubsub - Set a variable shared by all recipients.
+func (b *XSMTPAPIBuilder) SetPubsub(key string, value interface{}) *XSMTPAPIBuilder {
+	b.pubsub[wrapVarKey(key)] = value
+	return b
+}
+
+// SetFilters - Enable or disable subscription, open and click tracking.
+func (b *XSMTPAPIBuilder) SetFilters(subscriptionTracking, openTracking, clickTracking bool) *XSMTPAPIBuilder {
+	b.filters = &Filter{
+		SubscriptionTracking: TrackingFilter{Settings: FilterSettings{Enable: enableFlag(subscriptionTracking)}},
+		OpenTracking:         TrackingFilter{Settings: FilterSettings{Enable: enableFlag(openTracking)}},
+		ClickTracking:        TrackingFilter{Settings: FilterSettings{Enable: enableFlag(clickTracking)}},
+	}
+	return b
+}
+
+// SetUnsubscribePages - Set the unsubscribe pages used by the email.
+func (b *XSMTPAPIBuilder) SetUnsubscribePages(pageIDs ...int) *XSMTPAPIBuilder {
+	b.settings = &Settings{
+		Unsubscribe: UnsubscribeSettings{PageID: append([]int(nil), pageIDs...)},
+	}
+	return b
+}
+
+func enableFlag(enable bool) string {
+	if enable {
+		return "1"
+	}
+	return "0"
+}
+
+// Len returns the number of recipients added so far.
+func (b *XSMTPAPIBuilder) Len() int {
+	return len(b.to)
+}
+
+// Build - Build and validate the XSMTPAPI.
+func (b *XSMTPAPIBuilder) Build() (XSMTPAPI, error) {
+	if b.err != nil {
+		return XSMTPAPI{}, b.err
+	}
+	x := XSMTPAPI{
+		To:       append([]string(nil), b.to...),
+		Filters:  b.filters,
+		Settings: b.settings,
+	}
+	if len(b.sub) > 0 {
+		x.Sub = make(map[string][]interface{}, len(b.sub))
+		for key, values := range b.sub {
+			x.Sub[key] = append([]interface{}(nil), values...)
+		}
+	}
+	if len(b.pubsub) > 0 {
+		x.Pubsub = make(map[string]interface{}, len(b.pubsub))
+		for key, value := range b.pubsub {
+			x.Pubsub[key] = value
+		}
+	}
+	if err := x.validateXSMTPAPI(); err != nil {
+		return XSMTPAPI{}, err
+	}
+	return x, nil
+}
@@ -0,0 +1,84 @@
+// Package apierror classifies the errors returned by the SendCloud APIs.
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Sentinel errors matched by the APIError types through errors.Is.
+var (
+	ErrRateLimited      = errors.New("sendcloud: rate limited")
+	ErrInvalidRecipient = errors.New("sendcloud: invalid recipient")
+	ErrAuthFailure      = errors.New("sendcloud: authentication failed")
+	ErrTemplateNotFound = errors.New("sendcloud: template not found")
+)
+
+// Codes groups the SendCloud statusCode values of an API by the sentinel error they map to.
+type Codes struct {
+	RateLimited      []int
+	InvalidRecipient []int
+	AuthFailure      []int
+	TemplateNotFound []int
+}
+
+// Is reports whether an error with the given HTTP status and SendCloud
+// statusCode belongs to the category of the target sentinel error.
+func (c *Codes) Is(target error, httpStatus int, statusCode int) bool {
+	switch target {
+	case ErrRateLimited:
+		return httpStatus == http.StatusTooManyRequests || containsCode(c.RateLimited, statusCode)
+	case ErrInvalidRecipient:
+		return containsCode(c.InvalidRecipient, statusCode)
+	case ErrAuthFailure:
+		return httpStatus == http.StatusUnauthorized || httpStatus == http.StatusForbidden ||
+			containsCode(c.AuthFailure, statusCode)
+	case ErrTemplateNotFound:
+		return containsCode(c.TemplateNotFound, statusCode)
+	}
+	return false
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Format returns the message of an API error.
+func Format(endpoint string, httpStatus int, statusCode int, message string, requestID string) string {
+	code := statusCode
+	if code == 0 {
+		code = httpStatus
+	}
+	if len(requestID) > 0 {
+		return fmt.Sprintf("%v: %d %v (sendRequestId %v)", endpoint, code, message, requestID)
+	}
+	return fmt.Sprintf("%v: %d %v", endpoint, code, message)
+}
+
+// Parse reads the SendCloud statusCode and message of a failed response, falling
+// back to the HTTP status text when the body carries no message.
+func Parse(r *http.Response) (statusCode int, message string) {
+	if r.StatusCode == http.StatusNotFound {
+		return 0, "Not Found"
+	}
+	var body struct {
+		StatusCode int    `json:"statusCode"`
+		Message    string `json:"message"`
+	}
+	data, err := io.ReadAll(r.Body)
+	if err == nil && data != nil {
+		json.Unmarshal(data, &body)
+	}
+	if len(body.Message) == 0 {
+		body.Message = http.StatusText(r.StatusCode)
+	}
+	return body.StatusCode, body.Message
+}
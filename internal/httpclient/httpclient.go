@@ -0,0 +1,82 @@
+// Package httpclient validates the HTTP settings of the email and SMS clients.
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ParseBaseURL checks that baseURL is an absolute http(s) URL and returns it without a trailing slash.
+func ParseBaseURL(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if len(u.Host) == 0 {
+		return "", errors.New("host cannot be empty")
+	}
+	return strings.TrimSuffix(baseURL, "/"), nil
+}
+
+// ParseProxyURL parses the URL of an HTTP(S) proxy.
+func ParseProxyURL(proxyURL string) (*url.URL, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid proxy url %q", proxyURL)
+	}
+	return u, nil
+}
+
+// Clone returns a shallow copy so that options never modify
+// http.DefaultClient or a client owned by the caller.
+func Clone(httpClient *http.Client) *http.Client {
+	if httpClient == nil {
+		return &http.Client{}
+	}
+	c := *httpClient
+	return &c
+}
+
+// WithProxy returns a copy of httpClient that sends requests through proxy.
+func WithProxy(httpClient *http.Client, proxy *url.URL) (*http.Client, error) {
+	httpClient = Clone(httpClient)
+	var transport *http.Transport
+	switch t := httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, errors.New("the http client transport is not an *http.Transport")
+	}
+	transport.Proxy = http.ProxyURL(proxy)
+	httpClient.Transport = transport
+	return httpClient, nil
+}
+
+// Configure applies the timeout and proxy, when set, to a copy of httpClient.
+// Clients apply them after all their options ran, so that they are kept whatever
+// the position of the option that replaces the http.Client.
+func Configure(httpClient *http.Client, timeout *time.Duration, proxy *url.URL) (*http.Client, error) {
+	if proxy != nil {
+		var err error
+		if httpClient, err = WithProxy(httpClient, proxy); err != nil {
+			return nil, fmt.Errorf("WithProxy: %w", err)
+		}
+	}
+	if timeout != nil {
+		httpClient = Clone(httpClient)
+		httpClient.Timeout = *timeout
+	}
+	return httpClient, nil
+}
@@ -0,0 +1,42 @@
+// Package jsonnum decodes the numbers that the SendCloud APIs may encode as JSON strings.
+package jsonnum
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Int is an integer that SendCloud may encode as a JSON string.
+type Int int
+
+func (i *Int) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*i = 0
+		return nil
+	}
+	n, err := strconv.Atoi(string(data))
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", data)
+	}
+	*i = Int(n)
+	return nil
+}
+
+// Float is a number that SendCloud may encode as a JSON string.
+type Float float64
+
+func (f *Float) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if len(data) == 0 || string(data) == "null" {
+		*f = 0
+		return nil
+	}
+	n, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*f = Float(n)
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package listcache caches a list of the account, such as its labels or signs,
+// that is checked before every send.
+package listcache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxErrorTTL is how long a failed refresh is remembered, at most.
+const maxErrorTTL = 5 * time.Second
+
+// Cache holds the last list fetched successfully. A single fetch runs at a
+// time, outside the lock, and the callers that need the list wait for it. When
+// a refresh fails, the last good list keeps being served and no other fetch is
+// attempted for a short while; without a list the error is returned instead.
+type Cache struct {
+	ttl      time.Duration
+	errorTTL time.Duration
+
+	mu         sync.Mutex
+	value      interface{}
+	fetched    time.Time
+	err        error
+	failed     time.Time
+	generation int
+	call       *call
+}
+
+// call is a fetch in flight, shared by the callers waiting for it.
+type call struct {
+	generation int
+	cancel     context.CancelFunc
+	waiters    int
+	done       chan struct{}
+	value      interface{}
+	err        error
+}
+
+// New returns an empty cache that fetches the list again once it is older than ttl.
+func New(ttl time.Duration) *Cache {
+	errorTTL := maxErrorTTL
+	if ttl < errorTTL {
+		errorTTL = ttl
+	}
+	return &Cache{ttl: ttl, errorTTL: errorTTL}
+}
+
+// Get returns the cached list, calling fetch when it is missing or older than
+// the ttl. fetch must return a non-nil value on success. The fetch is not tied
+// to ctx: it is canceled only once every caller waiting for it has given up.
+func (c *Cache) Get(ctx context.Context, fetch func(context.Context) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if c.value != nil && time.Since(c.fetched) <= c.ttl {
+		value := c.value
+		c.mu.Unlock()
+		return value, nil
+	}
+	if !c.failed.IsZero() && time.Since(c.failed) <= c.errorTTL {
+		value, err := c.value, c.err
+		c.mu.Unlock()
+		if value != nil {
+			return value, nil
+		}
+		return nil, err
+	}
+	current := c.call
+	if current == nil {
+		fetchCtx, cancel := context.WithCancel(context.Background())
+		current = &call{generation: c.generation, cancel: cancel, done: make(chan struct{})}
+		c.call = current
+		go c.fetch(fetchCtx, current, fetch)
+	}
+	current.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-current.done:
+		return current.value, current.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		current.waiters--
+		if current.waiters == 0 {
+			// Nobody needs the list anymore; the next caller starts a new fetch.
+			current.cancel()
+			if c.call == current {
+				c.call = nil
+			}
+		}
+		c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Cache) fetch(ctx context.Context, current *call, fetch func(context.Context) (interface{}, error)) {
+	value, err := fetch(ctx)
+	canceled := ctx.Err() != nil
+	current.cancel()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.call == current {
+		c.call = nil
+	}
+	if current.generation == c.generation {
+		switch {
+		case err == nil:
+			c.value, c.fetched = value, time.Now()
+			c.err, c.failed = nil, time.Time{}
+		case !canceled:
+			c.err, c.failed = err, time.Now()
+		}
+	}
+	if err == nil {
+		current.value = value
+	} else if current.generation == c.generation && c.value != nil {
+		current.value = c.value
+	} else {
+		current.err = err
+	}
+	close(current.done)
+}
+
+// Invalidate drops the cached list, so that the next Get fetches it again.
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.value, c.err, c.failed = nil, nil, time.Time{}
+	c.generation++
+	c.call = nil
+}
@@ -0,0 +1,39 @@
+// Package quota polls the remaining sending quota of a SendCloud account.
+package quota
+
+import (
+	"context"
+	"time"
+)
+
+// Watch calls remaining every interval until ctx is done. onLow is called once
+// each time the remaining quota drops below threshold, starting with the first
+// poll, and again only after the quota went back above threshold. onError is
+// optional and called when a poll fails before ctx is done.
+func Watch(ctx context.Context, interval time.Duration, threshold int,
+	remaining func(context.Context) (int, error), onLow func(), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	low := false
+	for {
+		n, err := remaining(ctx)
+		switch {
+		case err != nil:
+			if onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		case n < threshold:
+			if !low {
+				onLow()
+			}
+			low = true
+		default:
+			low = false
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package ratelimit implements the client-side rate limiting of SendCloud sends.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Bucket is a token bucket: it allows bursts of up to burst sends and refills
+// at rate sends per second. It is safe for concurrent use.
+type Bucket struct {
+	rate  float64
+	burst float64
+
+	mu       sync.Mutex
+	tokens   float64
+	last     time.Time
+	requests int64
+	waits    int64
+	waitTime time.Duration
+}
+
+// Stats counts the sends that went through a Bucket and the time they spent waiting.
+type Stats struct {
+	Requests int64         // calls to Wait
+	Waits    int64         // calls to Wait that had to wait
+	WaitTime time.Duration // total time spent waiting
+}
+
+// New returns a full bucket that allows bursts of up to burst sends and
+// refills at rate sends per second.
+func New(rate float64, burst int) *Bucket {
+	return &Bucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a send is allowed or ctx is done.
+func (l *Bucket) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	// Take a token now, possibly going into debt; the debt is the wait.
+	l.tokens--
+	l.requests++
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	var err error
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err != nil {
+		// Give the token back, the send will not happen.
+		l.tokens++
+	}
+	l.waits++
+	l.waitTime += time.Since(now)
+	return err
+}
+
+// Stats returns the counters of the limiter.
+func (l *Bucket) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{Requests: l.requests, Waits: l.waits, WaitTime: l.waitTime}
+}
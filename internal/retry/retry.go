@@ -0,0 +1,187 @@
+// Package retry retries the requests of the email and SMS clients that fail
+// with a transient error.
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Policy controls how requests that fail with a transient error are retried.
+type Policy struct {
+	MaxAttempts     int           // total attempts including the first one
+	BaseDelay       time.Duration // delay before the first retry, doubled on every further retry
+	MaxDelay        time.Duration // upper bound of a single delay, including Retry-After
+	Jitter          float64       // random fraction in [0, 1] applied to each delay
+	RetryableStatus []int         // HTTP status codes that are retried
+}
+
+// DefaultPolicy retries gateway errors and throttling up to three attempts.
+var DefaultPolicy = Policy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+	RetryableStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// Validate reports whether the policy can be used.
+func (p *Policy) Validate() error {
+	switch {
+	case p.MaxAttempts < 1:
+		return errors.New("maxAttempts must be at least 1")
+	case p.BaseDelay < 0 || p.MaxDelay < 0:
+		return errors.New("delays cannot be negative")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return errors.New("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+func (p *Policy) isRetryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatus {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry (1 for the first retry).
+// The second return value is false when the server asked to wait longer than MaxDelay.
+func (p *Policy) backoff(retry int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && wait > p.MaxDelay {
+				return 0, false
+			}
+			return wait, true
+		}
+	}
+	delay := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(p.Jitter * (2*rand.Float64() - 1) * float64(delay))
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		wait := time.Until(t)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// Do sends the request with httpClient, retrying transient failures according to
+// policy when retryable is true and policy is not nil. Transport errors are only
+// retried when they are transient (see isTransient); any other error, such as one
+// returned while building the request body, is returned straight away. wait, if not nil, is called
+// after the backoff of every retry so that retries go through the same client-side
+// rate limit as first attempts. The caller must close the response body.
+func Do(ctx context.Context, httpClient *http.Client, req *http.Request, policy *Policy, retryable bool,
+	wait func(context.Context) error) (*http.Response, error) {
+	var reused bool
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			reused = info.Reused
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, trace))
+	for attempt := 1; ; attempt++ {
+		reused = false
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+		if !retryable || policy == nil || attempt >= policy.MaxAttempts {
+			return resp, err
+		}
+		if err == nil && !policy.isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if err != nil && !isTransient(err, reused) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, err
+		}
+		delay, ok := policy.backoff(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if wait != nil {
+			if err := wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		// Rebuild the body only once the retry is certain to be sent.
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// isTransient reports whether a transport error may not happen again on retry:
+// a timeout, a connection reset by the server, or a connection that was reused
+// from the pool and closed by the server before the response.
+func isTransient(err error, reused bool) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	return reused && (errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF))
+}
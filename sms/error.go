@@ -0,0 +1,89 @@
+package sendcloud
+
+import (
+	"errors"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/apierror"
+	"net/http"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	ErrRateLimited      = apierror.ErrRateLimited
+	ErrInvalidRecipient = apierror.ErrInvalidRecipient
+	ErrAuthFailure      = apierror.ErrAuthFailure
+	ErrTemplateNotFound = apierror.ErrTemplateNotFound
+)
+
+// apiCodes groups SendCloud statusCode values by the sentinel error they map to.
+var apiCodes = apierror.Codes{
+	RateLimited:      []int{402, 501},
+	InvalidRecipient: []int{411, 412, 413, 414},
+	AuthFailure:      []int{421, 431, 432},
+	TemplateNotFound: []int{471, 472},
+}
+
+// APIError is returned when SendCloud rejects a request, either with a non-200
+// HTTP status or with a statusCode other than 200 in the response body.
+type APIError struct {
+	HTTPStatus int    `json:"-"`          // HTTP status of the response
+	StatusCode int    `json:"statusCode"` // SendCloud statusCode from the response body, 0 if absent
+	Message    string `json:"message"`    // SendCloud message from the response body
+	Endpoint   string `json:"-"`          // path of the API endpoint that was called
+	RequestID  string `json:"-"`          // SendRequestId of the send, if any
+
+	response *http.Response
+}
+
+func (e *APIError) Error() string {
+	return apierror.Format(e.Endpoint, e.HTTPStatus, e.StatusCode, e.Message, e.RequestID)
+}
+
+// Is reports whether the error belongs to the category of the given sentinel error.
+func (e *APIError) Is(target error) bool {
+	return apiCodes.Is(target, e.HTTPStatus, e.StatusCode)
+}
+
+// As lets errors.As match the deprecated *ErrorResponse, which failed requests
+// returned before *APIError was introduced.
+func (e *APIError) As(target interface{}) bool {
+	errResp, ok := target.(**ErrorResponse)
+	if !ok {
+		return false
+	}
+	*errResp = &ErrorResponse{Response: e.response, Message: e.Message}
+	return true
+}
+
+// IsRateLimited reports whether err was caused by SendCloud throttling the request.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsInvalidRecipient reports whether err was caused by an invalid recipient phone number.
+func IsInvalidRecipient(err error) bool {
+	return errors.Is(err, ErrInvalidRecipient)
+}
+
+// IsAuthFailure reports whether err was caused by invalid smsUser/smsKey credentials or signature.
+func IsAuthFailure(err error) bool {
+	return errors.Is(err, ErrAuthFailure)
+}
+
+// IsTemplateNotFound reports whether err was caused by an unknown template id.
+func IsTemplateNotFound(err error) bool {
+	return errors.Is(err, ErrTemplateNotFound)
+}
+
+func checkResponse(r *http.Response) *APIError {
+	if r.StatusCode == http.StatusOK {
+		return nil
+	}
+	statusCode, message := apierror.Parse(r)
+	return &APIError{
+		HTTPStatus: r.StatusCode,
+		StatusCode: statusCode,
+		Message:    message,
+		Endpoint:   r.Request.URL.Path,
+		response:   r,
+	}
+}
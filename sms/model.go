@@ -1,7 +1,10 @@
 package sendcloud
 
 import (
+	"encoding/json"
 	"net/http"
+	"net/url"
+	"time"
 )
 
 const (
@@ -20,17 +23,60 @@ const (
 	sendSmsCodePath     = "/sendCode"
 )
 
+// Management endpoints, relative to the SMS API base.
+const (
+	templateListPath   = "/template/list"
+	templateGetPath    = "/template/get"
+	templateAddPath    = "/template/save"
+	templateUpdatePath = "/template/update"
+	templateDeletePath = "/template/delete"
+	templateSubmitPath = "/template/submit"
+
+	signListPath   = "/sign/list"
+	signGetPath    = "/sign/get"
+	signAddPath    = "/sign/save"
+	signUpdatePath = "/sign/update"
+	signDeletePath = "/sign/delete"
+
+	smsStatusPath      = "/data/status"
+	smsStatDayPath     = "/statday/list"
+	smsStatSummaryPath = "/statsum/list"
+
+	accountInfoPath = "/userinfo/get"
+)
+
 type SendCloudSms struct {
-	smsUser string
-	smsKey  string
-	apiBase string
-	client  *http.Client
+	smsUser   string
+	smsKey    string
+	apiBase   string
+	client    *http.Client
+	timeout   *time.Duration // set by WithTimeout, applied to client by the constructor
+	proxy     *url.URL       // set by WithProxy, applied to client by the constructor
+	userAgent string
+	retry     *RetryPolicy
+	signs     *signCache
+	limiters  map[string]*RateLimiter
+
+	Templates *SmsTemplateService
+	Signs     *SignService
+}
+
+// apiResponse is the envelope of every SendCloud API response.
+type apiResponse struct {
+	Result     bool            `json:"result"`
+	StatusCode int             `json:"statusCode"`
+	Message    string          `json:"message"`
+	Info       json.RawMessage `json:"info"`
 }
 
 type Response struct {
 	*http.Response
 }
 
+// ErrorResponse was the error returned for failed requests.
+//
+// Deprecated: failed requests now return *APIError, which still matches
+// *ErrorResponse through errors.As. Use *APIError in new code.
 type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 	Message  string         `json:"message"` // error message
@@ -66,8 +112,39 @@ type CodeSms struct {
 }
 
 type SendSmsResult struct {
-	Result     bool        `json:"result"`
-	StatusCode int         `json:"statusCode"`
-	Message    string      `json:"message"`
-	Info       interface{} `json:"info"`
+	Result     bool            `json:"result"`
+	StatusCode int             `json:"statusCode"`
+	Message    string          `json:"message"`
+	Info       SmsSendInfo     `json:"info"`
+	RawInfo    json.RawMessage `json:"-"` // undecoded info, for fields not covered by Info
+}
+
+// SmsSendInfo is the info returned by a send.
+type SmsSendInfo struct {
+	SuccessCount int      `json:"successCount"`
+	SmsIDs       []string `json:"smsIds"`
+}
+
+func (r *SendSmsResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Result     bool            `json:"result"`
+		StatusCode int             `json:"statusCode"`
+		Message    string          `json:"message"`
+		Info       json.RawMessage `json:"info"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	r.Result = raw.Result
+	r.StatusCode = raw.StatusCode
+	r.Message = raw.Message
+	r.RawInfo = raw.Info
+	r.Info = SmsSendInfo{}
+	// Failed requests may carry a non-object info, which is left to RawInfo.
+	if len(raw.Info) > 0 && raw.Info[0] == '{' {
+		if err := json.Unmarshal(raw.Info, &r.Info); err != nil {
+			return err
+		}
+	}
+	return nil
 }
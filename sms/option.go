@@ -0,0 +1,121 @@
+package sendcloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/httpclient"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/ratelimit"
+	"net/http"
+	"time"
+)
+
+// Version is the SDK version reported in the default User-Agent.
+const Version = "1.1.0"
+
+const defaultUserAgent = "sendcloud-sdk-go/" + Version
+
+// Option configures a SendCloudSms client created by NewSendCloudSms.
+type Option func(*SendCloudSms) error
+
+// WithBaseURL - Set the base URL of the SMS API, e.g. a regional endpoint or a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(client *SendCloudSms) error {
+		apiBase, err := httpclient.ParseBaseURL(baseURL)
+		if err != nil {
+			return fmt.Errorf("WithBaseURL: %w", err)
+		}
+		client.apiBase = apiBase
+		return nil
+	}
+}
+
+// WithHTTPClient - Set the http.Client used to send requests. WithTimeout and
+// WithProxy are applied to a copy of it, whatever the order of the options.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *SendCloudSms) error {
+		if httpClient == nil {
+			return errors.New("WithHTTPClient: httpClient cannot be nil")
+		}
+		client.client = httpClient
+		return nil
+	}
+}
+
+// WithTimeout - Set the timeout of every request sent by the client.
+func WithTimeout(timeout time.Duration) Option {
+	return func(client *SendCloudSms) error {
+		if timeout < 0 {
+			return errors.New("WithTimeout: timeout cannot be negative")
+		}
+		client.timeout = &timeout
+		return nil
+	}
+}
+
+// WithUserAgent - Set the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(client *SendCloudSms) error {
+		if len(userAgent) == 0 {
+			return errors.New("WithUserAgent: userAgent cannot be empty")
+		}
+		client.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithProxy - Send requests through the given HTTP(S) proxy.
+func WithProxy(proxyURL string) Option {
+	return func(client *SendCloudSms) error {
+		proxy, err := httpclient.ParseProxyURL(proxyURL)
+		if err != nil {
+			return fmt.Errorf("WithProxy: %w", err)
+		}
+		client.proxy = proxy
+		return nil
+	}
+}
+
+// WithRetry - Retry sends that carry a SendRequestId when they fail with a transient error.
+func WithRetry(policy RetryPolicy) Option {
+	return func(client *SendCloudSms) error {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("WithRetry: %w", err)
+		}
+		client.retry = &policy
+		return nil
+	}
+}
+
+// WithSignCheck - Check that the sign of a CodeSms exists and is approved before sending,
+// failing with ErrSignNotFound or ErrSignNotApproved otherwise. The signs are cached for ttl;
+// when listing them again fails, the last list is used and the failure is remembered briefly.
+func WithSignCheck(ttl time.Duration) Option {
+	return func(client *SendCloudSms) error {
+		if ttl <= 0 {
+			return errors.New("WithSignCheck: ttl must be positive")
+		}
+		client.signs = newSignCache(ttl)
+		return nil
+	}
+}
+
+// WithRateLimit - Limit the sends to an endpoint (ENDPOINT_SEND_TEMPLATE, ENDPOINT_SEND_VOICE
+// or ENDPOINT_SEND_CODE) to rate per second, in bursts of up to burst. Sends over the
+// limit wait for their turn, or fail when their context is done first.
+func WithRateLimit(endpoint string, rate float64, burst int) Option {
+	return func(client *SendCloudSms) error {
+		switch {
+		case !isValidEndpoint(endpoint):
+			return fmt.Errorf("WithRateLimit: endpoint [%s] is illegal", endpoint)
+		case rate <= 0:
+			return errors.New("WithRateLimit: rate must be positive")
+		case burst < 1:
+			return errors.New("WithRateLimit: burst must be at least 1")
+		}
+		if client.limiters == nil {
+			client.limiters = make(map[string]*RateLimiter)
+		}
+		client.limiters[endpoint] = ratelimit.New(rate, burst)
+		return nil
+	}
+}
@@ -12,6 +12,11 @@ import (
 )
 
 func (client *SendCloudSms) calculateSignature(params url.Values) string {
+	return signParams(client.smsKey, params)
+}
+
+// signParams signs every param but smsKey and signature, sorted by name, with smsKey.
+func signParams(smsKey string, params url.Values) string {
 	sortedParams := url.Values{}
 
 	for k, v := range params {
@@ -36,7 +41,7 @@ func (client *SendCloudSms) calculateSignature(params url.Values) string {
 		paramStr = paramStr[:len(paramStr)-1]
 	}
 
-	signStr := client.smsKey + "&" + paramStr + "&" + client.smsKey
+	signStr := smsKey + "&" + paramStr + "&" + smsKey
 
 	hasher := sha256.New()
 	hasher.Write([]byte(signStr))
@@ -127,3 +132,12 @@ func (client *SendCloudSms) prepareSendCodeSmsParams(args *CodeSms) (url.Values,
 	}
 	return params, nil
 }
+
+func setPage(params url.Values, start int, limit int) {
+	if start > 0 {
+		params.Set("start", strconv.Itoa(start))
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+}
@@ -0,0 +1,85 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/jsonnum"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/quota"
+	"net/url"
+	"time"
+)
+
+// Quota is the daily sending quota of the account.
+type Quota struct {
+	Total int // messages allowed today
+	Used  int // messages sent today
+}
+
+// Remaining returns the number of messages that can still be sent today.
+func (q *Quota) Remaining() int {
+	return q.Total - q.Used
+}
+
+// Balance is the prepaid balance of the account.
+type Balance struct {
+	Balance   float64
+	Available float64 // balance not reserved by pending sends
+}
+
+// accountInfo is the info of the account endpoint.
+type accountInfo struct {
+	Quota            jsonnum.Int   `json:"quota"`
+	UsedQuota        jsonnum.Int   `json:"usedQuota"`
+	Balance          jsonnum.Float `json:"balance"`
+	AvailableBalance jsonnum.Float `json:"availableBalance"`
+}
+
+// GetQuota - Get the daily sending quota of the account.
+func (client *SendCloudSms) GetQuota(ctx context.Context) (*Quota, error) {
+	info := new(accountInfo)
+	if err := client.call(ctx, accountInfoPath, url.Values{}, info, true); err != nil {
+		return nil, fmt.Errorf("GetQuota: %w", err)
+	}
+	return &Quota{Total: int(info.Quota), Used: int(info.UsedQuota)}, nil
+}
+
+// GetBalance - Get the prepaid balance of the account.
+func (client *SendCloudSms) GetBalance(ctx context.Context) (*Balance, error) {
+	info := new(accountInfo)
+	if err := client.call(ctx, accountInfoPath, url.Values{}, info, true); err != nil {
+		return nil, fmt.Errorf("GetBalance: %w", err)
+	}
+	return &Balance{Balance: float64(info.Balance), Available: float64(info.AvailableBalance)}, nil
+}
+
+// QuotaWatch configures WatchQuota.
+type QuotaWatch struct {
+	Interval  time.Duration // time between two polls
+	Threshold int           // OnLow is called when the remaining quota drops below Threshold
+	OnLow     func(*Quota)
+	OnError   func(error) // optional, called when a poll fails
+}
+
+// WatchQuota - Poll the quota in the background until ctx is done. OnLow is called
+// once each time the remaining quota drops below the threshold, starting with the
+// first poll, and again only after the quota went back above the threshold.
+func (client *SendCloudSms) WatchQuota(ctx context.Context, watch QuotaWatch) error {
+	switch {
+	case watch.Interval <= 0:
+		return errors.New("WatchQuota: interval must be positive")
+	case watch.OnLow == nil:
+		return errors.New("WatchQuota: onLow cannot be nil")
+	}
+	var last *Quota
+	remaining := func(ctx context.Context) (int, error) {
+		q, err := client.GetQuota(ctx)
+		if err != nil {
+			return 0, err
+		}
+		last = q
+		return q.Remaining(), nil
+	}
+	go quota.Watch(ctx, watch.Interval, watch.Threshold, remaining, func() { watch.OnLow(last) }, watch.OnError)
+	return nil
+}
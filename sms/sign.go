@@ -0,0 +1,190 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/listcache"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Errors returned by SendCodeSms for the sign of the message, when the client
+// was created with WithSignCheck.
+var (
+	ErrSignNotFound    = errors.New("sign not found")
+	ErrSignNotApproved = errors.New("sign not approved")
+)
+
+// SignService manages the signs referenced by CodeSms.SignId and CodeSms.SignName.
+type SignService struct {
+	client *SendCloudSms
+}
+
+type SmsSign struct {
+	SignID      int    `json:"signId"`
+	SignName    string `json:"signName"`
+	AuditStatus int    `json:"auditStatus"` // one of the AUDIT_* states
+	AuditReason string `json:"auditReason"`
+	GmtCreated  string `json:"gmtCreated"`
+	GmtUpdated  string `json:"gmtUpdated"`
+}
+
+// Approved reports whether the sign can be used to send.
+func (s *SmsSign) Approved() bool {
+	return s.AuditStatus == AUDIT_APPROVED
+}
+
+// SignListOptions filters and paginates Signs.List.
+type SignListOptions struct {
+	AuditStatus *int // one of the AUDIT_* states, all states when nil
+	Start       int
+	Limit       int
+}
+
+// SignList is a page of signs.
+type SignList struct {
+	Total int       `json:"total"`
+	Signs []SmsSign `json:"dataList"`
+}
+
+// List - List signs, one page at a time.
+func (s *SignService) List(ctx context.Context, opts *SignListOptions) (*SignList, error) {
+	params := url.Values{}
+	if opts != nil {
+		if err := validatePage(opts.Start, opts.Limit); err != nil {
+			return nil, fmt.Errorf("Signs.List: %w", err)
+		}
+		if opts.AuditStatus != nil {
+			params.Set("auditStatus", strconv.Itoa(*opts.AuditStatus))
+		}
+		setPage(params, opts.Start, opts.Limit)
+	}
+	list := new(SignList)
+	if err := s.client.call(ctx, signListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("Signs.List: %w", err)
+	}
+	return list, nil
+}
+
+// Get - Get a sign by its ID.
+func (s *SignService) Get(ctx context.Context, signID int) (*SmsSign, error) {
+	if signID <= 0 {
+		return nil, errors.New("Signs.Get: signId must be positive")
+	}
+	params := url.Values{}
+	params.Set("signId", strconv.Itoa(signID))
+	var info struct {
+		Data SmsSign `json:"data"`
+	}
+	if err := s.client.call(ctx, signGetPath, params, &info, true); err != nil {
+		return nil, fmt.Errorf("Signs.Get: %w", err)
+	}
+	return &info.Data, nil
+}
+
+// Create - Create a sign and submit it for review. It returns the sign with its ID.
+func (s *SignService) Create(ctx context.Context, signName string) (*SmsSign, error) {
+	if signName == "" {
+		return nil, errors.New("Signs.Create: signName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("signName", signName)
+	var info struct {
+		Data SmsSign `json:"data"`
+	}
+	if err := s.client.call(ctx, signAddPath, params, &info, false); err != nil {
+		return nil, fmt.Errorf("Signs.Create: %w", err)
+	}
+	s.client.signs.invalidate()
+	return &info.Data, nil
+}
+
+// Update - Rename a sign, which submits it for review again.
+func (s *SignService) Update(ctx context.Context, signID int, signName string) error {
+	if signID <= 0 {
+		return errors.New("Signs.Update: signId must be positive")
+	}
+	if signName == "" {
+		return errors.New("Signs.Update: signName cannot be empty")
+	}
+	params := url.Values{}
+	params.Set("signId", strconv.Itoa(signID))
+	params.Set("signName", signName)
+	if err := s.client.call(ctx, signUpdatePath, params, nil, false); err != nil {
+		return fmt.Errorf("Signs.Update: %w", err)
+	}
+	s.client.signs.invalidate()
+	return nil
+}
+
+// Delete - Delete a sign by its ID.
+func (s *SignService) Delete(ctx context.Context, signID int) error {
+	if signID <= 0 {
+		return errors.New("Signs.Delete: signId must be positive")
+	}
+	params := url.Values{}
+	params.Set("signId", strconv.Itoa(signID))
+	if err := s.client.call(ctx, signDeletePath, params, nil, false); err != nil {
+		return fmt.Errorf("Signs.Delete: %w", err)
+	}
+	s.client.signs.invalidate()
+	return nil
+}
+
+// signCache caches the signs of the account for WithSignCheck.
+// A nil cache disables the check.
+type signCache struct {
+	signs *listcache.Cache
+}
+
+func newSignCache(ttl time.Duration) *signCache {
+	return &signCache{signs: listcache.New(ttl)}
+}
+
+// check returns ErrSignNotFound or ErrSignNotApproved unless the sign with
+// signID, or else signName, is approved. The signs are listed again once the
+// cached list is older than the ttl.
+func (c *signCache) check(ctx context.Context, s *SignService, signID int, signName string) error {
+	if c == nil || (signID == 0 && signName == "") {
+		return nil
+	}
+	cached, err := c.signs.Get(ctx, func(ctx context.Context) (interface{}, error) {
+		signs := []SmsSign{}
+		for start := 0; ; start += MAX_PAGE_LIMIT {
+			list, err := s.List(ctx, &SignListOptions{Start: start, Limit: MAX_PAGE_LIMIT})
+			if err != nil {
+				return nil, err
+			}
+			signs = append(signs, list.Signs...)
+			if len(list.Signs) < MAX_PAGE_LIMIT || start+len(list.Signs) >= list.Total {
+				return signs, nil
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	signs := cached.([]SmsSign)
+	for i := range signs {
+		sign := &signs[i]
+		if (signID != 0 && sign.SignID == signID) || (signID == 0 && sign.SignName == signName) {
+			if !sign.Approved() {
+				return fmt.Errorf("%w: %s", ErrSignNotApproved, sign.SignName)
+			}
+			return nil
+		}
+	}
+	if signID != 0 {
+		return fmt.Errorf("%w: %d", ErrSignNotFound, signID)
+	}
+	return fmt.Errorf("%w: %s", ErrSignNotFound, signName)
+}
+
+func (c *signCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.signs.Invalidate()
+}
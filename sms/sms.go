@@ -2,35 +2,60 @@ package sendcloud
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/httpclient"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
-func NewSendCloudSms(smsUser string, smsKey string) (*SendCloudSms, error) {
+func NewSendCloudSms(smsUser string, smsKey string, opts ...Option) (*SendCloudSms, error) {
 	switch {
 	case len(smsUser) == 0:
 		return nil, errors.New("NewSendCloudSms: smsUser cannot be empty")
 	case len(smsKey) == 0:
 		return nil, errors.New("NewSendCloudSms: smsKey cannot be empty")
 	}
-	return &SendCloudSms{
-		smsUser: smsUser,
-		smsKey:  smsKey,
-		apiBase: smsBasePath,
-		client:  http.DefaultClient,
-	}, nil
+	sc := &SendCloudSms{
+		smsUser:   smsUser,
+		smsKey:    smsKey,
+		apiBase:   smsBasePath,
+		client:    http.DefaultClient,
+		userAgent: defaultUserAgent,
+	}
+	sc.Templates = &SmsTemplateService{client: sc}
+	sc.Signs = &SignService{client: sc}
+	for _, opt := range opts {
+		if err := opt(sc); err != nil {
+			return nil, fmt.Errorf("NewSendCloudSms: %w", err)
+		}
+	}
+	httpClient, err := httpclient.Configure(sc.client, sc.timeout, sc.proxy)
+	if err != nil {
+		return nil, fmt.Errorf("NewSendCloudSms: %w", err)
+	}
+	sc.client = httpClient
+	return sc, nil
 }
 
 func (client *SendCloudSms) SendTemplateSms(args *TemplateSms) (*SendSmsResult, error) {
+	return client.SendTemplateSmsWithContext(context.Background(), args)
+}
+
+func (client *SendCloudSms) SendTemplateSmsWithContext(ctx context.Context, args *TemplateSms) (*SendSmsResult, error) {
 	if err := client.validateConfig(); err != nil {
 		return nil, fmt.Errorf("SendTemplateSms: %w", err)
 	}
 	if err := args.validateTemplateSms(); err != nil {
 		return nil, fmt.Errorf("SendTemplateSms: %w", err)
 	}
+	if err := client.wait(ctx, ENDPOINT_SEND_TEMPLATE); err != nil {
+		return nil, fmt.Errorf("SendTemplateSms: %w", err)
+	}
 	params, err := client.prepareSendTemplateSmsParams(args)
 	if err != nil {
 		return nil, fmt.Errorf("SendTemplateSms: %w", err)
@@ -45,7 +70,7 @@ func (client *SendCloudSms) SendTemplateSms(args *TemplateSms) (*SendSmsResult,
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	responseData := new(SendSmsResult)
-	err = client.request(req, responseData)
+	err = client.request(ctx, ENDPOINT_SEND_TEMPLATE, req, responseData, args.SendRequestId)
 	if err != nil {
 		return responseData, err
 	}
@@ -53,12 +78,19 @@ func (client *SendCloudSms) SendTemplateSms(args *TemplateSms) (*SendSmsResult,
 }
 
 func (client *SendCloudSms) SendVoiceSms(args *VoiceSms) (*SendSmsResult, error) {
+	return client.SendVoiceSmsWithContext(context.Background(), args)
+}
+
+func (client *SendCloudSms) SendVoiceSmsWithContext(ctx context.Context, args *VoiceSms) (*SendSmsResult, error) {
 	if err := client.validateConfig(); err != nil {
 		return nil, fmt.Errorf("SendVoiceSms: %w", err)
 	}
 	if err := args.validateVoiceSms(); err != nil {
 		return nil, fmt.Errorf("SendVoiceSms: %w", err)
 	}
+	if err := client.wait(ctx, ENDPOINT_SEND_VOICE); err != nil {
+		return nil, fmt.Errorf("SendVoiceSms: %w", err)
+	}
 	params, err := client.prepareSendVoiceSmsParams(args)
 	if err != nil {
 		return nil, fmt.Errorf("SendVoiceSms: %w", err)
@@ -73,7 +105,7 @@ func (client *SendCloudSms) SendVoiceSms(args *VoiceSms) (*SendSmsResult, error)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	responseData := new(SendSmsResult)
-	err = client.request(req, responseData)
+	err = client.request(ctx, ENDPOINT_SEND_VOICE, req, responseData, args.SendRequestId)
 	if err != nil {
 		return responseData, err
 	}
@@ -81,12 +113,22 @@ func (client *SendCloudSms) SendVoiceSms(args *VoiceSms) (*SendSmsResult, error)
 }
 
 func (client *SendCloudSms) SendCodeSms(args *CodeSms) (*SendSmsResult, error) {
+	return client.SendCodeSmsWithContext(context.Background(), args)
+}
+
+func (client *SendCloudSms) SendCodeSmsWithContext(ctx context.Context, args *CodeSms) (*SendSmsResult, error) {
 	if err := client.validateConfig(); err != nil {
 		return nil, fmt.Errorf("SendCodeSms: %w", err)
 	}
 	if err := args.validateCodeSms(); err != nil {
 		return nil, fmt.Errorf("SendCodeSms: %w", err)
 	}
+	if err := client.signs.check(ctx, client.Signs, args.SignId, args.SignName); err != nil {
+		return nil, fmt.Errorf("SendCodeSms: %w", err)
+	}
+	if err := client.wait(ctx, ENDPOINT_SEND_CODE); err != nil {
+		return nil, fmt.Errorf("SendCodeSms: %w", err)
+	}
 	params, err := client.prepareSendCodeSmsParams(args)
 	if err != nil {
 		return nil, fmt.Errorf("SendCodeSms: %w", err)
@@ -101,23 +143,64 @@ func (client *SendCloudSms) SendCodeSms(args *CodeSms) (*SendSmsResult, error) {
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	responseData := new(SendSmsResult)
-	err = client.request(req, responseData)
+	err = client.request(ctx, ENDPOINT_SEND_CODE, req, responseData, args.SendRequestId)
 	if err != nil {
 		return responseData, err
 	}
 	return responseData, nil
 }
 
-func (client *SendCloudSms) request(req *http.Request, responseResult *SendSmsResult) error {
-	resp, err := client.client.Do(req)
+// call sends a signed management request and decodes the info of the response into info.
+func (client *SendCloudSms) call(ctx context.Context, path string, params url.Values, info interface{}, retryable bool) error {
+	if err := client.validateConfig(); err != nil {
+		return err
+	}
+	params.Set("smsUser", client.smsUser)
+	params.Set("timestamp", strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10))
+	params.Set("signature", client.calculateSignature(params))
+	req, err := http.NewRequest("POST", client.apiBase+path, bytes.NewBufferString(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.do(ctx, req, "", retryable)
 	if err != nil {
 		return err
 	}
-	err = checkResponse(resp)
+	defer resp.Body.Close()
+
+	if apiError := checkResponse(resp); apiError != nil {
+		return apiError
+	}
+	var response apiResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return &APIError{
+			HTTPStatus: resp.StatusCode,
+			StatusCode: response.StatusCode,
+			Message:    response.Message,
+			Endpoint:   req.URL.Path,
+			response:   resp,
+		}
+	}
+	if info != nil && len(response.Info) > 0 && string(response.Info) != "null" {
+		return json.Unmarshal(response.Info, info)
+	}
+	return nil
+}
+
+func (client *SendCloudSms) request(ctx context.Context, endpoint string, req *http.Request, responseResult *SendSmsResult, sendRequestId string) error {
+	resp, err := client.do(ctx, req, endpoint, len(sendRequestId) > 0)
 	if err != nil {
-		defer resp.Body.Close()
 		return err
 	}
+	defer resp.Body.Close()
+	if apiError := checkResponse(resp); apiError != nil {
+		apiError.RequestID = sendRequestId
+		return apiError
+	}
 
 	if responseResult != nil {
 		err = json.NewDecoder(resp.Body).Decode(responseResult)
@@ -125,28 +208,19 @@ func (client *SendCloudSms) request(req *http.Request, responseResult *SendSmsRe
 			return err
 		}
 		if responseResult.StatusCode != http.StatusOK {
-			return errors.New(responseResult.Message)
+			return &APIError{
+				HTTPStatus: resp.StatusCode,
+				StatusCode: responseResult.StatusCode,
+				Message:    responseResult.Message,
+				Endpoint:   req.URL.Path,
+				RequestID:  sendRequestId,
+				response:   resp,
+			}
 		}
 	}
 	return err
 }
 
-func checkResponse(r *http.Response) error {
-	if r.StatusCode == http.StatusOK {
-		return nil
-	}
-	errorResponse := &ErrorResponse{Response: r}
-	if r.StatusCode == http.StatusNotFound {
-		errorResponse.Message = "Not Found"
-		return errorResponse
-	}
-	data, err := io.ReadAll(r.Body)
-	if err == nil && data != nil {
-		json.Unmarshal(data, errorResponse)
-	}
-	return errorResponse
-}
-
 func (r *ErrorResponse) Error() string {
 	return fmt.Sprintf("%v %v: %d %v",
 		r.Response.Request.Method, r.Response.Request.URL,
@@ -0,0 +1,161 @@
+package sendcloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/internal/jsonnum"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Groupings of SMS statistics, for SmsStatsQuery.GroupBy.
+const (
+	STATS_GROUP_TEMPLATE = "templateId"
+	STATS_GROUP_MSG_TYPE = "msgType"
+	STATS_GROUP_LABEL    = "labelId"
+)
+
+// SmsStatsQuery filters and groups the statistics returned by GetDailySmsStats and GetSmsStatsSummary.
+type SmsStatsQuery struct {
+	StartDate   time.Time
+	EndDate     time.Time // StartDate when zero
+	TemplateIDs []int
+	LabelIDs    []int
+	MsgType     *int     // SMS, MMS, INTERNAT_SMS, VOICE..., all types when nil
+	GroupBy     []string // STATS_GROUP_* values; the fields not grouped by are zero in the result
+}
+
+// SmsStats are the SMS statistics of one row: a day and/or a group.
+type SmsStats struct {
+	Date        string // yyyy-MM-dd, daily statistics only
+	TemplateID  int
+	MsgType     int
+	LabelID     int
+	Requests    int
+	Delivered   int
+	Failed      int
+	Pending     int // no operator report yet
+	Billed      int // billed messages; a long SMS is billed as several messages
+	Cost        float64
+	SuccessRate float64 // Delivered / Requests, 0 without requests
+}
+
+func (s *SmsStats) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		SendDate     string        `json:"sendDate"`
+		TemplateID   jsonnum.Int   `json:"templateId"`
+		MsgType      jsonnum.Int   `json:"msgType"`
+		LabelID      jsonnum.Int   `json:"labelId"`
+		RequestNum   jsonnum.Int   `json:"requestNum"`
+		DeliveredNum jsonnum.Int   `json:"deliveredNum"`
+		FailedNum    jsonnum.Int   `json:"failedNum"`
+		UnknownNum   jsonnum.Int   `json:"unknownNum"`
+		ChargeNum    jsonnum.Int   `json:"chargeNum"`
+		Fee          jsonnum.Float `json:"fee"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = SmsStats{
+		Date:       raw.SendDate,
+		TemplateID: int(raw.TemplateID),
+		MsgType:    int(raw.MsgType),
+		LabelID:    int(raw.LabelID),
+		Requests:   int(raw.RequestNum),
+		Delivered:  int(raw.DeliveredNum),
+		Failed:     int(raw.FailedNum),
+		Pending:    int(raw.UnknownNum),
+		Billed:     int(raw.ChargeNum),
+		Cost:       float64(raw.Fee),
+	}
+	if s.Requests > 0 {
+		s.SuccessRate = float64(s.Delivered) / float64(s.Requests)
+	}
+	return nil
+}
+
+// GetDailySmsStats - Get the SMS statistics of each day in the query range, per group.
+func (client *SendCloudSms) GetDailySmsStats(ctx context.Context, query *SmsStatsQuery) ([]SmsStats, error) {
+	stats, err := client.getSmsStats(ctx, smsStatDayPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("GetDailySmsStats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetSmsStatsSummary - Get the SMS statistics of the whole query range, per group.
+func (client *SendCloudSms) GetSmsStatsSummary(ctx context.Context, query *SmsStatsQuery) ([]SmsStats, error) {
+	stats, err := client.getSmsStats(ctx, smsStatSummaryPath, query)
+	if err != nil {
+		return nil, fmt.Errorf("GetSmsStatsSummary: %w", err)
+	}
+	return stats, nil
+}
+
+func (client *SendCloudSms) getSmsStats(ctx context.Context, path string, query *SmsStatsQuery) ([]SmsStats, error) {
+	if query == nil {
+		return nil, errors.New("query cannot be nil")
+	}
+	if err := query.validateSmsStatsQuery(); err != nil {
+		return nil, err
+	}
+	var info struct {
+		DataList []SmsStats `json:"dataList"`
+	}
+	if err := client.call(ctx, path, query.prepareSmsStatsParams(), &info, true); err != nil {
+		return nil, err
+	}
+	return info.DataList, nil
+}
+
+func (q *SmsStatsQuery) validateSmsStatsQuery() error {
+	switch {
+	case q.StartDate.IsZero():
+		return errors.New("startDate cannot be empty")
+	case !q.EndDate.IsZero() && q.EndDate.Before(q.StartDate):
+		return errors.New("endDate cannot be before startDate")
+	case q.MsgType != nil && !isValidMsgType(*q.MsgType):
+		return errors.New("msgType value is illegal")
+	}
+	for _, group := range q.GroupBy {
+		if group != STATS_GROUP_TEMPLATE && group != STATS_GROUP_MSG_TYPE && group != STATS_GROUP_LABEL {
+			return fmt.Errorf("groupBy [%s] is illegal", group)
+		}
+	}
+	return nil
+}
+
+func (q *SmsStatsQuery) prepareSmsStatsParams() url.Values {
+	params := url.Values{}
+	params.Set("startDate", q.StartDate.Format(statusDateLayout))
+	endDate := q.EndDate
+	if endDate.IsZero() {
+		endDate = q.StartDate
+	}
+	params.Set("endDate", endDate.Format(statusDateLayout))
+	if len(q.TemplateIDs) > 0 {
+		params.Set("templateIdList", joinInts(q.TemplateIDs))
+	}
+	if len(q.LabelIDs) > 0 {
+		params.Set("labelIdList", joinInts(q.LabelIDs))
+	}
+	if q.MsgType != nil {
+		params.Set("msgType", strconv.Itoa(*q.MsgType))
+	}
+	if len(q.GroupBy) > 0 {
+		params.Set("groupBy", strings.Join(q.GroupBy, ","))
+	}
+	return params
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, value := range values {
+		strs[i] = strconv.Itoa(value)
+	}
+	return strings.Join(strs, ",")
+}
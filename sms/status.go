@@ -0,0 +1,178 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMS delivery statuses, as reported by QuerySmsStatus.
+const (
+	SMS_STATUS_SUBMITTED = "submitted" // accepted by SendCloud, waiting for the operator report
+	SMS_STATUS_DELIVERED = "delivered"
+	SMS_STATUS_FAILED    = "failed"
+)
+
+const statusDateLayout = "2006-01-02"
+
+// SmsStatusQuery filters and paginates QuerySmsStatus. StartDate is required.
+type SmsStatusQuery struct {
+	SmsIDs        []string // as returned in SendSmsResult.Info.SmsIDs
+	Phone         string
+	SendRequestID string
+	TemplateID    int
+	LabelID       int
+	StartDate     time.Time
+	EndDate       time.Time // StartDate when zero
+	Start         int
+	Limit         int
+}
+
+// SmsRecord is the delivery record of an SMS to one phone.
+type SmsRecord struct {
+	SmsID         string `json:"smsId"`
+	Phone         string `json:"phone"`
+	MsgType       int    `json:"msgType"`
+	TemplateID    int    `json:"templateId"`
+	LabelID       int    `json:"labelId"`
+	SendRequestID string `json:"sendRequestId"`
+	Status        string `json:"status"`    // one of the SMS_STATUS_* values
+	ErrorCode     string `json:"errorCode"` // operator error code of a failed SMS
+	Message       string `json:"message"`
+	SendTime      string `json:"sendTime"`
+	ReportTime    string `json:"reportTime"`
+}
+
+// SmsRecordList is a page of delivery records.
+type SmsRecordList struct {
+	Total   int         `json:"total"`
+	Records []SmsRecord `json:"dataList"`
+}
+
+// QuerySmsStatus - Query the delivery records of sent SMS, one page at a time.
+func (client *SendCloudSms) QuerySmsStatus(ctx context.Context, query *SmsStatusQuery) (*SmsRecordList, error) {
+	if query == nil {
+		return nil, errors.New("QuerySmsStatus: query cannot be nil")
+	}
+	if err := query.validateSmsStatusQuery(); err != nil {
+		return nil, fmt.Errorf("QuerySmsStatus: %w", err)
+	}
+	list := new(SmsRecordList)
+	if err := client.call(ctx, smsStatusPath, query.prepareSmsStatusParams(), list, true); err != nil {
+		return nil, fmt.Errorf("QuerySmsStatus: %w", err)
+	}
+	return list, nil
+}
+
+// IterateSmsStatus - Iterate over every delivery record matching query, fetching
+// pages of query.Limit records (MAX_PAGE_LIMIT when zero) as needed.
+func (client *SendCloudSms) IterateSmsStatus(ctx context.Context, query *SmsStatusQuery) *SmsRecordIterator {
+	it := &SmsRecordIterator{ctx: ctx, client: client, index: -1}
+	if query != nil {
+		it.query = *query
+	}
+	if it.query.Limit == 0 {
+		it.query.Limit = MAX_PAGE_LIMIT
+	}
+	return it
+}
+
+func (q *SmsStatusQuery) validateSmsStatusQuery() error {
+	if err := validatePage(q.Start, q.Limit); err != nil {
+		return err
+	}
+	switch {
+	case q.StartDate.IsZero():
+		return errors.New("startDate cannot be empty")
+	case !q.EndDate.IsZero() && q.EndDate.Before(q.StartDate):
+		return errors.New("endDate cannot be before startDate")
+	}
+	return nil
+}
+
+func (q *SmsStatusQuery) prepareSmsStatusParams() url.Values {
+	params := url.Values{}
+	if len(q.SmsIDs) > 0 {
+		params.Set("smsIds", strings.Join(q.SmsIDs, ","))
+	}
+	if len(q.Phone) > 0 {
+		params.Set("phone", q.Phone)
+	}
+	if len(q.SendRequestID) > 0 {
+		params.Set("sendRequestId", q.SendRequestID)
+	}
+	if q.TemplateID != 0 {
+		params.Set("templateId", strconv.Itoa(q.TemplateID))
+	}
+	if q.LabelID != 0 {
+		params.Set("labelId", strconv.Itoa(q.LabelID))
+	}
+	params.Set("startDate", q.StartDate.Format(statusDateLayout))
+	endDate := q.EndDate
+	if endDate.IsZero() {
+		endDate = q.StartDate
+	}
+	params.Set("endDate", endDate.Format(statusDateLayout))
+	setPage(params, q.Start, q.Limit)
+	return params
+}
+
+// SmsRecordIterator iterates over the delivery records matching a query:
+//
+//	it := client.IterateSmsStatus(ctx, &sendcloud.SmsStatusQuery{StartDate: day})
+//	for it.Next() {
+//		fmt.Println(it.Record().Status)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle the error
+//	}
+type SmsRecordIterator struct {
+	ctx    context.Context
+	client *SendCloudSms
+	query  SmsStatusQuery
+	page   []SmsRecord
+	index  int
+	done   bool
+	err    error
+}
+
+// Next advances to the next record, fetching the next page when needed.
+// It returns false at the end of the records or on error.
+func (it *SmsRecordIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		return true
+	}
+	if it.done {
+		return false
+	}
+	list, err := it.client.QuerySmsStatus(it.ctx, &it.query)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.query.Start += len(list.Records)
+	if len(list.Records) < it.query.Limit || it.query.Start >= list.Total {
+		it.done = true
+	}
+	it.page = list.Records
+	it.index = 0
+	return len(it.page) > 0
+}
+
+// Record returns the current record.
+func (it *SmsRecordIterator) Record() SmsRecord {
+	return it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *SmsRecordIterator) Err() error {
+	return it.err
+}
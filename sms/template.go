@@ -0,0 +1,180 @@
+package sendcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Audit states of SMS templates and signs.
+const (
+	AUDIT_NOT_SUBMITTED = -1
+	AUDIT_PENDING       = 0
+	AUDIT_APPROVED      = 1
+	AUDIT_REJECTED      = 2
+)
+
+// SMS template types.
+const (
+	SMS_TYPE_CODE      = 0 // verification code
+	SMS_TYPE_NOTICE    = 1 // notification
+	SMS_TYPE_MARKETING = 2 // marketing
+)
+
+// SmsTemplateService manages the SMS templates referenced by TemplateSms.TemplateId.
+type SmsTemplateService struct {
+	client *SendCloudSms
+}
+
+type SmsTemplate struct {
+	TemplateID   int    `json:"templateId"`
+	TemplateName string `json:"templateName"`
+	Content      string `json:"templateContent"` // variables are written as %name%
+	MsgType      int    `json:"msgType"`         // SMS, MMS, INTERNAT_SMS...
+	SmsType      int    `json:"smsType"`         // SMS_TYPE_CODE, SMS_TYPE_NOTICE or SMS_TYPE_MARKETING
+	AuditStatus  int    `json:"auditStatus"`
+	AuditReason  string `json:"auditReason"`
+	GmtCreated   string `json:"gmtCreated"`
+	GmtUpdated   string `json:"gmtUpdated"`
+}
+
+// Approved reports whether the template can be used to send.
+func (t *SmsTemplate) Approved() bool {
+	return t.AuditStatus == AUDIT_APPROVED
+}
+
+// SmsTemplateListOptions filters and paginates Templates.List.
+type SmsTemplateListOptions struct {
+	AuditStatus *int // one of the AUDIT_* states, all states when nil
+	MsgType     *int
+	Start       int
+	Limit       int
+}
+
+// SmsTemplateList is a page of templates.
+type SmsTemplateList struct {
+	Total     int           `json:"total"`
+	Templates []SmsTemplate `json:"dataList"`
+}
+
+// List - List templates, one page at a time.
+func (s *SmsTemplateService) List(ctx context.Context, opts *SmsTemplateListOptions) (*SmsTemplateList, error) {
+	params := url.Values{}
+	if opts != nil {
+		if err := validatePage(opts.Start, opts.Limit); err != nil {
+			return nil, fmt.Errorf("Templates.List: %w", err)
+		}
+		if opts.AuditStatus != nil {
+			params.Set("auditStatus", strconv.Itoa(*opts.AuditStatus))
+		}
+		if opts.MsgType != nil {
+			params.Set("msgType", strconv.Itoa(*opts.MsgType))
+		}
+		setPage(params, opts.Start, opts.Limit)
+	}
+	list := new(SmsTemplateList)
+	if err := s.client.call(ctx, templateListPath, params, list, true); err != nil {
+		return nil, fmt.Errorf("Templates.List: %w", err)
+	}
+	return list, nil
+}
+
+// Get - Get a template by its ID.
+func (s *SmsTemplateService) Get(ctx context.Context, templateID int) (*SmsTemplate, error) {
+	if templateID <= 0 {
+		return nil, errors.New("Templates.Get: templateId must be positive")
+	}
+	params := url.Values{}
+	params.Set("templateId", strconv.Itoa(templateID))
+	var info struct {
+		Data SmsTemplate `json:"data"`
+	}
+	if err := s.client.call(ctx, templateGetPath, params, &info, true); err != nil {
+		return nil, fmt.Errorf("Templates.Get: %w", err)
+	}
+	return &info.Data, nil
+}
+
+// Create - Create a template and return it with its ID. Set submit to send it for review right away.
+func (s *SmsTemplateService) Create(ctx context.Context, template *SmsTemplate, submit bool) (*SmsTemplate, error) {
+	if err := template.validateSmsTemplate(); err != nil {
+		return nil, fmt.Errorf("Templates.Create: %w", err)
+	}
+	params := template.prepareSmsTemplateParams()
+	params.Set("isSubmitAudit", strconv.FormatBool(submit))
+	var info struct {
+		Data SmsTemplate `json:"data"`
+	}
+	if err := s.client.call(ctx, templateAddPath, params, &info, false); err != nil {
+		return nil, fmt.Errorf("Templates.Create: %w", err)
+	}
+	return &info.Data, nil
+}
+
+// Update - Update the template with the same ID. Set submit to send it for review right away.
+func (s *SmsTemplateService) Update(ctx context.Context, template *SmsTemplate, submit bool) error {
+	if template.TemplateID <= 0 {
+		return errors.New("Templates.Update: templateId must be positive")
+	}
+	if err := template.validateSmsTemplate(); err != nil {
+		return fmt.Errorf("Templates.Update: %w", err)
+	}
+	params := template.prepareSmsTemplateParams()
+	params.Set("templateId", strconv.Itoa(template.TemplateID))
+	params.Set("isSubmitAudit", strconv.FormatBool(submit))
+	if err := s.client.call(ctx, templateUpdatePath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Update: %w", err)
+	}
+	return nil
+}
+
+// Delete - Delete a template by its ID.
+func (s *SmsTemplateService) Delete(ctx context.Context, templateID int) error {
+	if templateID <= 0 {
+		return errors.New("Templates.Delete: templateId must be positive")
+	}
+	params := url.Values{}
+	params.Set("templateId", strconv.Itoa(templateID))
+	if err := s.client.call(ctx, templateDeletePath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Delete: %w", err)
+	}
+	return nil
+}
+
+// Submit - Submit a template for review.
+func (s *SmsTemplateService) Submit(ctx context.Context, templateID int) error {
+	if templateID <= 0 {
+		return errors.New("Templates.Submit: templateId must be positive")
+	}
+	params := url.Values{}
+	params.Set("templateId", strconv.Itoa(templateID))
+	if err := s.client.call(ctx, templateSubmitPath, params, nil, false); err != nil {
+		return fmt.Errorf("Templates.Submit: %w", err)
+	}
+	return nil
+}
+
+func (t *SmsTemplate) validateSmsTemplate() error {
+	switch {
+	case len(t.TemplateName) == 0:
+		return errors.New("templateName cannot be empty")
+	case len(t.Content) == 0:
+		return errors.New("templateContent cannot be empty")
+	case !isValidMsgType(t.MsgType):
+		return errors.New("msgType value is illegal")
+	case t.SmsType != SMS_TYPE_CODE && t.SmsType != SMS_TYPE_NOTICE && t.SmsType != SMS_TYPE_MARKETING:
+		return errors.New("smsType value is illegal")
+	}
+	return nil
+}
+
+func (t *SmsTemplate) prepareSmsTemplateParams() url.Values {
+	params := url.Values{}
+	params.Set("templateName", t.TemplateName)
+	params.Set("templateContent", t.Content)
+	params.Set("msgType", strconv.Itoa(t.MsgType))
+	params.Set("smsType", strconv.Itoa(t.SmsType))
+	return params
+}
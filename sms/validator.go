@@ -2,13 +2,23 @@ package sendcloud
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 )
 
+const MAX_PAGE_LIMIT = 100
+
 func (client *SendCloudSms) validateConfig() error {
 	if len(client.apiBase) == 0 {
 		client.apiBase = smsBasePath
 	}
+	if client.client == nil {
+		client.client = http.DefaultClient
+	}
+	if len(client.userAgent) == 0 {
+		client.userAgent = defaultUserAgent
+	}
 	switch {
 	case len(client.smsUser) == 0:
 		return errors.New("smsUser cannot be empty")
@@ -85,3 +95,13 @@ func (s *CodeSms) validateCodeSms() error {
 	}
 	return nil
 }
+
+func validatePage(start int, limit int) error {
+	switch {
+	case start < 0:
+		return errors.New("start cannot be negative")
+	case limit < 0 || limit > MAX_PAGE_LIMIT:
+		return fmt.Errorf("limit must be between 0 and %d (0 for the default)", MAX_PAGE_LIMIT)
+	}
+	return nil
+}
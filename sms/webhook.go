@@ -0,0 +1,156 @@
+package sendcloud
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Events posted to the SMS callback URL.
+const (
+	SMS_EVENT_DELIVER       = "deliver"      // delivered to the phone
+	SMS_EVENT_WORKER_ERROR  = "workererror"  // rejected before reaching the operator
+	SMS_EVENT_DELIVER_ERROR = "delivererror" // rejected by the operator
+	SMS_EVENT_REPLY         = "reply"        // upstream (MO) reply of the phone owner
+)
+
+// SmsEvent holds the fields common to every SMS callback.
+type SmsEvent struct {
+	Type       string
+	SmsID      string
+	Phone      string
+	TemplateID int
+	LabelID    int
+	Tag        map[string]string // the Tag of the send
+	Timestamp  time.Time
+	Form       url.Values // every field posted by SendCloud
+}
+
+// DeliveredEvent reports that an SMS reached the phone.
+type DeliveredEvent struct {
+	SmsEvent
+}
+
+// FailedEvent reports that an SMS could not be delivered.
+type FailedEvent struct {
+	SmsEvent
+	ErrorCode string // operator error code
+	Message   string
+}
+
+// ReplyEvent is an SMS sent back by the phone owner.
+type ReplyEvent struct {
+	SmsEvent
+	Content   string
+	ReplyTime time.Time
+}
+
+// WebhookHandler is an http.Handler that verifies and dispatches SendCloud SMS
+// callbacks. Register the callbacks before serving requests. An event without a
+// callback is acknowledged and dropped. A callback returning an error makes the
+// handler answer 500, so that SendCloud posts the event again later.
+type WebhookHandler struct {
+	smsKey      string
+	onDelivered func(*DeliveredEvent) error
+	onFailed    func(*FailedEvent) error
+	onReply     func(*ReplyEvent) error
+}
+
+// NewWebhookHandler - Create a WebhookHandler verifying callbacks with smsKey.
+func NewWebhookHandler(smsKey string) (*WebhookHandler, error) {
+	if len(smsKey) == 0 {
+		return nil, errors.New("NewWebhookHandler: smsKey cannot be empty")
+	}
+	return &WebhookHandler{smsKey: smsKey}, nil
+}
+
+// OnDelivered - Set the callback of delivered SMS.
+func (h *WebhookHandler) OnDelivered(fn func(*DeliveredEvent) error) { h.onDelivered = fn }
+
+// OnFailed - Set the callback of SMS that could not be delivered.
+func (h *WebhookHandler) OnFailed(fn func(*FailedEvent) error) { h.onFailed = fn }
+
+// OnReply - Set the callback of replies.
+func (h *WebhookHandler) OnReply(fn func(*ReplyEvent) error) { h.onReply = fn }
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	form := r.PostForm
+	expected := signParams(h.smsKey, form)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(form.Get("signature"))) != 1 {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	event, err := newSmsEvent(form)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.dispatch(event); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+func (h *WebhookHandler) dispatch(event SmsEvent) error {
+	form := event.Form
+	switch event.Type {
+	case SMS_EVENT_DELIVER:
+		if h.onDelivered != nil {
+			return h.onDelivered(&DeliveredEvent{SmsEvent: event})
+		}
+	case SMS_EVENT_WORKER_ERROR, SMS_EVENT_DELIVER_ERROR:
+		if h.onFailed != nil {
+			return h.onFailed(&FailedEvent{SmsEvent: event, ErrorCode: form.Get("errorCode"), Message: form.Get("message")})
+		}
+	case SMS_EVENT_REPLY:
+		if h.onReply != nil {
+			replyTime, _ := parseMillis(form.Get("replyTime"))
+			return h.onReply(&ReplyEvent{SmsEvent: event, Content: form.Get("content"), ReplyTime: replyTime})
+		}
+	}
+	return nil
+}
+
+func newSmsEvent(form url.Values) (SmsEvent, error) {
+	event := SmsEvent{
+		Type:  form.Get("event"),
+		SmsID: form.Get("smsId"),
+		Phone: form.Get("phone"),
+		Form:  form,
+	}
+	event.TemplateID, _ = strconv.Atoi(form.Get("templateId"))
+	event.LabelID, _ = strconv.Atoi(form.Get("labelId"))
+	if tag := form.Get("tag"); len(tag) > 0 {
+		if err := json.Unmarshal([]byte(tag), &event.Tag); err != nil {
+			return SmsEvent{}, errors.New("invalid tag")
+		}
+	}
+	timestamp, err := parseMillis(form.Get("timestamp"))
+	if err != nil {
+		return SmsEvent{}, errors.New("invalid timestamp")
+	}
+	event.Timestamp = timestamp
+	return event, nil
+}
+
+// parseMillis parses a Unix timestamp in milliseconds, as sent by SendCloud.
+func parseMillis(timestamp string) (time.Time, error) {
+	n, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, n*int64(time.Millisecond)), nil
+}
@@ -0,0 +1,578 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/email"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newManagementServer serves the given JSON info for each path and records the
+// form of the last request to every path and the number of calls.
+func newManagementServer(t *testing.T, infos map[string]string) (*httptest.Server, map[string]url.Values, map[string]int) {
+	forms := make(map[string]url.Values)
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		info, ok := infos[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.PostForm.Get("apiUser") != "*" || r.PostForm.Get("apiKey") != "*" {
+			t.Errorf("missing credentials for %s", r.URL.Path)
+		}
+		forms[r.URL.Path] = r.PostForm
+		calls[r.URL.Path]++
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":` + info + `}`))
+	}))
+	return server, forms, calls
+}
+
+func TestTemplates(t *testing.T) {
+	server, forms, _ := newManagementServer(t, map[string]string{
+		"/template/list":   `{"total":2,"dataList":[{"invokeName":"welcome","name":"Welcome","templateType":0,"templateStat":1},{"invokeName":"digest","templateType":1}]}`,
+		"/template/get":    `{"data":{"invokeName":"welcome","subject":"Hi","html":"<p>Hi</p>","templateStat":0}}`,
+		"/template/add":    `{}`,
+		"/template/submit": `{}`,
+		"/template/delete": `{}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	batch := sendcloud.TEMPLATE_BATCH
+	list, err := client.Templates.List(ctx, &sendcloud.TemplateListOptions{TemplateType: &batch, Start: 10, Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 2 || len(list.Templates) != 2 || list.Templates[0].TemplateStat != sendcloud.TEMPLATE_STAT_APPROVED {
+		t.Errorf("unexpected list %+v", list)
+	}
+	if form := forms["/template/list"]; form.Get("templateType") != "1" || form.Get("start") != "10" || form.Get("limit") != "50" {
+		t.Errorf("unexpected list params %v", form)
+	}
+
+	template, err := client.Templates.Get(ctx, "welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.Html != "<p>Hi</p>" || template.TemplateStat != sendcloud.TEMPLATE_STAT_PENDING {
+		t.Errorf("unexpected template %+v", template)
+	}
+
+	err = client.Templates.Create(ctx, &sendcloud.Template{
+		InvokeName:   "welcome_fr",
+		Name:         "Welcome (fr)",
+		Subject:      "Bienvenue",
+		Html:         "<p>Bienvenue</p>",
+		TemplateType: sendcloud.TEMPLATE_TRIGGER,
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form := forms["/template/add"]; form.Get("invokeName") != "welcome_fr" || form.Get("isSubmitAudit") != "true" {
+		t.Errorf("unexpected add params %v", form)
+	}
+	if err := client.Templates.Submit(ctx, "welcome_fr"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Templates.Delete(ctx, "welcome_fr"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Templates.Create(ctx, &sendcloud.Template{InvokeName: "missing_fields"}, false); err == nil {
+		t.Error("expected validation error")
+	}
+}
+
+func TestAddressLists(t *testing.T) {
+	server, forms, calls := newManagementServer(t, map[string]string{
+		"/addresslist/list":     `{"total":1,"dataList":[{"address":"news@maillist.sendcloud.org","name":"News","membersCount":2}]}`,
+		"/addresslist/add":      `{}`,
+		"/addressmember/list":   `{"total":2,"dataList":[{"member":"a@ifaxin.com","name":"a","vars":"{\"%name%\":\"jack\"}"},{"member":"b@ifaxin.com","vars":{"%name%":"rose"}}]}`,
+		"/addressmember/add":    `{}`,
+		"/addressmember/delete": `{}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	lists, err := client.AddressLists.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lists.Total != 1 || lists.AddressLists[0].MembersCount != 2 {
+		t.Errorf("unexpected lists %+v", lists)
+	}
+	err = client.AddressLists.Create(ctx, &sendcloud.AddressList{Address: "vip@maillist.sendcloud.org", Name: "VIP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := client.AddressLists.ListMembers(ctx, "news@maillist.sendcloud.org", &sendcloud.ListOptions{Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members.Members) != 2 || members.Members[0].Vars["%name%"] != "jack" || members.Members[1].Vars["%name%"] != "rose" {
+		t.Errorf("unexpected members %+v", members)
+	}
+
+	var imports []sendcloud.AddressMember
+	for i := 0; i < 2500; i++ {
+		imports = append(imports, sendcloud.AddressMember{
+			Member: fmt.Sprintf("user%d@ifaxin.com", i),
+			Name:   fmt.Sprintf("user%d", i),
+			Vars:   map[string]interface{}{"name": fmt.Sprintf("user%d", i)},
+		})
+	}
+	added, err := client.AddressLists.AddMembers(ctx, "news@maillist.sendcloud.org", imports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 2500 || calls["/addressmember/add"] != 3 {
+		t.Errorf("expected 2500 members in 3 calls, got %d in %d", added, calls["/addressmember/add"])
+	}
+	form := forms["/addressmember/add"]
+	if len(strings.Split(form.Get("members"), ";")) != 500 {
+		t.Errorf("unexpected last chunk %v", form.Get("members"))
+	}
+	var vars []map[string]string
+	if err := json.Unmarshal([]byte(form.Get("vars")), &vars); err != nil || vars[0]["%name%"] != "user2000" {
+		t.Errorf("unexpected vars %v %v", vars, err)
+	}
+
+	removed, err := client.AddressLists.RemoveMembers(ctx, "news@maillist.sendcloud.org", []string{"a@ifaxin.com"})
+	if err != nil || removed != 1 {
+		t.Fatalf("unexpected remove result %d %v", removed, err)
+	}
+}
+
+func TestQueryEmailStatus(t *testing.T) {
+	server, forms, _ := newManagementServer(t, map[string]string{
+		"/data/emailStatus": `{"total":1,"dataList":[{"emailId":"1500000000000_1_1_1.sc-10_9_13_213-inbound0$a@ifaxin.com","sendRequestId":"req-1","recipient":"a@ifaxin.com","status":"open","sendLog":[{"event":"request","time":"2024-01-02 10:00:00"},{"event":"deliver","time":"2024-01-02 10:00:03"},{"event":"open","time":"2024-01-02 11:12:00"}]}]}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	list, err := client.QueryEmailStatus(ctx, &sendcloud.EmailStatusQuery{
+		SendRequestID: "req-1",
+		StartTime:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Status:        sendcloud.EMAIL_STATUS_OPEN,
+		Limit:         20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 1 || len(list.Statuses[0].Events) != 3 || list.Statuses[0].Events[1].Event != sendcloud.EMAIL_STATUS_DELIVER {
+		t.Errorf("unexpected statuses %+v", list)
+	}
+	form := forms["/data/emailStatus"]
+	if form.Get("sendRequestId") != "req-1" || form.Get("startTime") != "2024-01-02 00:00:00" || form.Get("status") != "open" || form.Get("limit") != "20" {
+		t.Errorf("unexpected params %v", form)
+	}
+
+	if _, err := client.QueryEmailStatus(ctx, &sendcloud.EmailStatusQuery{Status: sendcloud.EMAIL_STATUS_OPEN}); err == nil {
+		t.Error("expected error without filters")
+	}
+	if _, err := client.QueryEmailStatus(ctx, &sendcloud.EmailStatusQuery{Recipient: "a@ifaxin.com", Status: "lost"}); err == nil {
+		t.Error("expected error for illegal status")
+	}
+}
+
+func TestStats(t *testing.T) {
+	server, forms, _ := newManagementServer(t, map[string]string{
+		"/statday/list":  `{"dataList":[{"sendDate":"2024-01-02","apiUser":"news","requestNum":"120","deliveredNum":"118","invalidEmailsNum":"1","bounceNum":"1","openNum":"40","clickNum":"12","unsubscribeNum":"2","spamReportedNum":"0"}]}`,
+		"/stathour/list": `{"dataList":[{"sendDate":"2024-01-02","sendHour":9,"requestNum":30,"deliveredNum":30}]}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	daily, err := client.GetDailyStats(ctx, &sendcloud.StatsQuery{StartDate: day, LabelIDs: []int{1, 2}, Domains: []string{"mail.example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily) != 1 || daily[0].Requests != 120 || daily[0].Delivered != 118 || daily[0].Opens != 40 || daily[0].Unsubscribes != 2 {
+		t.Errorf("unexpected daily stats %+v", daily)
+	}
+	form := forms["/statday/list"]
+	if form.Get("startDate") != "2024-01-02" || form.Get("endDate") != "2024-01-02" || form.Get("labelIdList") != "1;2" || form.Get("domainList") != "mail.example.com" {
+		t.Errorf("unexpected params %v", form)
+	}
+
+	hourly, err := client.GetHourlyStats(ctx, &sendcloud.StatsQuery{StartDate: day, EndDate: day})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hourly) != 1 || hourly[0].Hour != 9 || hourly[0].Requests != 30 {
+		t.Errorf("unexpected hourly stats %+v", hourly)
+	}
+	if _, err := client.GetDailyStats(ctx, &sendcloud.StatsQuery{StartDate: day, EndDate: day.AddDate(0, 0, -1)}); err == nil {
+		t.Error("expected error for reversed range")
+	}
+}
+
+func TestSuppressions(t *testing.T) {
+	var deleted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/bounces/list":
+			if email := r.PostForm.Get("email"); email != "" {
+				w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"total":1,"dataList":[{"email":"` + email + `","reason":"mailbox full"}]}}`))
+				return
+			}
+			start, _ := strconv.Atoi(r.PostForm.Get("start"))
+			limit, _ := strconv.Atoi(r.PostForm.Get("limit"))
+			var rows []string
+			for i := start; i < start+limit && i < 5; i++ {
+				rows = append(rows, fmt.Sprintf(`{"email":"user%d@ifaxin.com"}`, i))
+			}
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"total":5,"dataList":[` + strings.Join(rows, ",") + `]}}`))
+		case "/bounces/delete":
+			deleted = r.PostForm.Get("email")
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	var emails []string
+	it := client.Bounces.Iterate(ctx, &sendcloud.SuppressionListOptions{Limit: 2})
+	for it.Next() {
+		emails = append(emails, it.Suppression().Email)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 5 || emails[4] != "user4@ifaxin.com" {
+		t.Errorf("unexpected emails %v", emails)
+	}
+
+	suppression, err := client.Bounces.Query(ctx, "a@ifaxin.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suppression == nil || suppression.Reason != "mailbox full" {
+		t.Errorf("unexpected suppression %+v", suppression)
+	}
+	if err := client.Bounces.Delete(ctx, "a@ifaxin.com", "b@ifaxin.com"); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != "a@ifaxin.com;b@ifaxin.com" {
+		t.Errorf("unexpected delete %s", deleted)
+	}
+	if err := client.Unsubscribes.Delete(ctx); err == nil {
+		t.Error("expected error without emails")
+	}
+}
+
+func TestLabels(t *testing.T) {
+	server, forms, calls := newManagementServer(t, map[string]string{
+		"/label/list": `{"total":2,"dataList":[{"labelId":1,"labelName":"welcome"},{"labelId":2,"labelName":"digest"}]}`,
+		"/label/add":  `{"data":{"labelId":3,"labelName":"reset"}}`,
+		"/mail/send":  `{"emailIdList":["1@ifaxin.com"]}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"), sendcloud.WithLabelCheck(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	label, err := client.Labels.Create(ctx, "reset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.LabelID != 3 || forms["/label/add"].Get("labelName") != "reset" {
+		t.Errorf("unexpected label %+v", label)
+	}
+
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+		Body:     sendcloud.MailBody{From: "SendCloud@SendCloud.com", Subject: "Hi", LabelName: "welcome"},
+		Content:  sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendCommonEmail(ctx, args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	args.Body.LabelName = "welcom"
+	if _, err := client.SendCommonEmail(ctx, args); !errors.Is(err, sendcloud.ErrLabelNotFound) {
+		t.Errorf("expected ErrLabelNotFound, got %v", err)
+	}
+	if calls["/label/list"] != 1 || calls["/mail/send"] != 2 {
+		t.Errorf("expected 1 label lookup and 2 sends, got %d and %d", calls["/label/list"], calls["/mail/send"])
+	}
+}
+
+func TestLabelsInvalidID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := client.Labels.Get(ctx, 0); err == nil {
+		t.Error("expected error for id 0")
+	}
+	if err := client.Labels.Update(ctx, 0, "name"); err == nil {
+		t.Error("expected error for id 0")
+	}
+	if err := client.Labels.Delete(ctx, -1); err == nil {
+		t.Error("expected error for id -1")
+	}
+}
+
+func TestLabelCheckRefresh(t *testing.T) {
+	var lists, failing int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/label/list":
+			if atomic.AddInt32(&lists, 1) == 1 {
+				<-release
+			}
+			if atomic.LoadInt32(&failing) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"total":1,"dataList":[{"labelId":1,"labelName":"welcome"}]}}`))
+		case "/label/add":
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"data":{"labelId":2,"labelName":"reset"}}}`))
+		default:
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"), sendcloud.WithLabelCheck(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+		Body:     sendcloud.MailBody{From: "SendCloud@SendCloud.com", Subject: "Hi", LabelName: "welcome"},
+		Content:  sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+
+	// Concurrent sends share one lookup, and a caller giving up does not fail the others.
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := client.SendCommonEmail(context.Background(), args)
+			errs <- err
+		}()
+	}
+	for atomic.LoadInt32(&lists) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.SendCommonEmail(canceled, args); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled send to fail with its context, got %v", err)
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+	if n := atomic.LoadInt32(&lists); n != 1 {
+		t.Errorf("expected 1 label lookup, got %d", n)
+	}
+
+	// A failed lookup is remembered for a while instead of being retried by every send.
+	atomic.StoreInt32(&failing, 1)
+	if _, err := client.Labels.Create(context.Background(), "reset"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendCommonEmail(context.Background(), args); err == nil {
+			t.Error("expected the lookup error")
+		}
+	}
+	if n := atomic.LoadInt32(&lists); n != 2 {
+		t.Errorf("expected 2 label lookups, got %d", n)
+	}
+}
+
+func TestLabelCheckServesLastGoodList(t *testing.T) {
+	var lists, failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/label/list" {
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+			return
+		}
+		atomic.AddInt32(&lists, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"total":1,"dataList":[{"labelId":1,"labelName":"welcome"}]}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"), sendcloud.WithLabelCheck(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+		Body:     sendcloud.MailBody{From: "SendCloud@SendCloud.com", Subject: "Hi", LabelName: "welcome"},
+		Content:  sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+	if _, err := client.SendCommonEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&failing, 1)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.SendCommonEmail(context.Background(), args); err != nil {
+		t.Errorf("expected the last good list to be used, got %v", err)
+	}
+	if n := atomic.LoadInt32(&lists); n != 2 {
+		t.Errorf("expected 2 label lookups, got %d", n)
+	}
+}
+
+func TestDomainsAndAPIUsers(t *testing.T) {
+	server, forms, _ := newManagementServer(t, map[string]string{
+		"/domain/list":  `{"total":1,"dataList":[{"name":"mail.example.com","verify":0,"spf":{"type":"TXT","name":"mail.example.com","value":"v=spf1 include:spf.sendcloud.org -all","verify":1},"dkim":{"type":"TXT","name":"mail._domainkey.mail.example.com","value":"k=rsa; p=MIGf","verify":0},"mx":{"type":"MX","name":"mail.example.com","value":"mx.sendcloud.net","verify":1},"cname":{"type":"CNAME","name":"links.mail.example.com","value":"sctrack.sendcloud.net","verify":1}}]}`,
+		"/domain/add":   `{"data":{"name":"mail.example.org","verify":0}}`,
+		"/apiuser/list": `{"total":1,"dataList":[{"name":"brand_trigger","cType":0,"domainName":"mail.example.com"}]}`,
+		"/apiuser/add":  `{"data":{"name":"brand_batch","apiKey":"secret","cType":1,"domainName":"mail.example.com"}}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	domain, err := client.Domains.Get(ctx, "mail.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pending []string
+	for _, record := range domain.DNSRecords() {
+		if !record.Verified() {
+			pending = append(pending, record.Name)
+		}
+	}
+	if len(pending) != 1 || pending[0] != "mail._domainkey.mail.example.com" {
+		t.Errorf("unexpected pending records %v", pending)
+	}
+	if _, err := client.Domains.Get(ctx, "unknown.example.com"); err == nil {
+		t.Error("expected error for unknown domain")
+	}
+	if _, err := client.Domains.Create(ctx, "mail.example.org"); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := client.APIUsers.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users.Total != 1 || users.APIUsers[0].DomainName != "mail.example.com" {
+		t.Errorf("unexpected users %+v", users)
+	}
+	user, err := client.APIUsers.Create(ctx, &sendcloud.APIUser{Name: "brand_batch", Type: sendcloud.APIUSER_BATCH, DomainName: "mail.example.com", OpenTrack: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.APIKey != "secret" || forms["/apiuser/add"].Get("cType") != "1" || forms["/apiuser/add"].Get("openTrack") != "true" {
+		t.Errorf("unexpected user %+v %v", user, forms["/apiuser/add"])
+	}
+}
+
+func TestQuota(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The remaining quota goes 900, 50, 40, 1000, 10...
+		used := []int{100, 950, 960, 0, 990}[int(atomic.AddInt32(&polls, 1)-1)%5]
+		fmt.Fprintf(w, `{"result":true,"statusCode":200,"message":"ok","info":{"quota":"1000","usedQuota":"%d","balance":"12.50","availableBalance":"10"}}`, used)
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL+"/mail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := client.GetBalance(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 12.5 || balance.Available != 10 {
+		t.Errorf("unexpected balance %+v", balance)
+	}
+
+	atomic.StoreInt32(&polls, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lows := make(chan int, 10)
+	err = client.WatchQuota(ctx, sendcloud.QuotaWatch{
+		Interval:  time.Millisecond,
+		Threshold: 100,
+		OnLow:     func(quota *sendcloud.Quota) { lows <- quota.Remaining() },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []int{50, 10} {
+		select {
+		case remaining := <-lows:
+			if remaining != expected {
+				t.Errorf("expected remaining quota %d, got %d", expected, remaining)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for low quota")
+		}
+	}
+	if err := client.WatchQuota(ctx, sendcloud.QuotaWatch{Threshold: 100}); err == nil {
+		t.Error("expected error without interval")
+	}
+}
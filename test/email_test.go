@@ -1,10 +1,22 @@
 package test
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/sendcloud2013/sendcloud-sdk-go/email"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -229,3 +241,902 @@ func TestSendEmailCalendar(t *testing.T) {
 	}
 	t.Log(result)
 }
+
+func TestNewSendCloudWithOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/send" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "my-app/1.0" {
+			t.Errorf("unexpected User-Agent %q", ua)
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL+"/"),
+		sendcloud.WithHTTPClient(server.Client()),
+		sendcloud.WithTimeout(5*time.Second),
+		sendcloud.WithUserAgent("my-app/1.0"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:    "SendCloud@SendCloud.com",
+			Subject: "Email from SendCloud SDK",
+		},
+		Content: sendcloud.TextContent{
+			Html: "<p>This is an HTML email.</p>",
+		},
+	}
+	result, err := client.SendCommonEmail(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(result)
+}
+
+func TestNewSendCloudOptionsOrder(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+		Body:     sendcloud.MailBody{From: "SendCloud@SendCloud.com", Subject: "Hi"},
+		Content:  sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+	// WithTimeout and WithProxy are kept when WithHTTPClient comes after them.
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithTimeout(50*time.Millisecond),
+		sendcloud.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendCommonEmail(context.Background(), args); err == nil {
+		t.Error("expected the request to time out")
+	}
+	client, err = sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithProxy("http://127.0.0.1:1"),
+		sendcloud.WithHTTPClient(server.Client()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendCommonEmail(context.Background(), args); err == nil {
+		t.Error("expected the request to go through the unreachable proxy")
+	}
+}
+
+func TestNewSendCloudInvalidOptions(t *testing.T) {
+	if _, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL("ftp://example.com")); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+	if _, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithProxy("not a url")); err == nil {
+		t.Error("expected error for invalid proxy")
+	}
+	if _, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithHTTPClient(nil)); err == nil {
+		t.Error("expected error for nil http client")
+	}
+}
+
+func TestSendCommonEmailRetry(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("to") != "a@ifaxin.com" {
+			t.Errorf("request body was not rewound: %v", r.PostForm)
+		}
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithRetry(sendcloud.RetryPolicy{
+			MaxAttempts:     3,
+			BaseDelay:       time.Millisecond,
+			MaxDelay:        10 * time.Millisecond,
+			Jitter:          0.5,
+			RetryableStatus: []int{http.StatusServiceUnavailable},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:          "SendCloud@SendCloud.com",
+			Subject:       "Email from SendCloud SDK",
+			SendRequestID: "retry-1",
+		},
+		Content: sendcloud.TextContent{
+			Html: "<p>This is an HTML email.</p>",
+		},
+	}
+	if _, err := client.SendCommonEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+
+	// Without a SendRequestID the send is not retried.
+	atomic.StoreInt32(&attempts, 0)
+	args.Body.SendRequestID = ""
+	if _, err := client.SendCommonEmail(context.Background(), args); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestSendTemplateEmailAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":false,"statusCode":40005,"message":"auth failed","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.TemplateMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:          "SendCloud@SendCloud.com",
+			Subject:       "Email from SendCloud SDK",
+			SendRequestID: "req-1",
+		},
+		TemplateInvokeName: "test_template_active",
+	}
+	_, err = client.SendTemplateEmail(context.Background(), args)
+	var apiErr *sendcloud.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != 40005 || apiErr.RequestID != "req-1" || apiErr.Endpoint != "/sendtemplate" {
+		t.Errorf("unexpected error fields %+v", apiErr)
+	}
+	if !sendcloud.IsAuthFailure(err) || sendcloud.IsRateLimited(err) {
+		t.Errorf("unexpected error classification for %v", err)
+	}
+	var errResp *sendcloud.ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("expected *APIError to match *ErrorResponse, got %v", err)
+	}
+	if errResp.Message != "auth failed" || errResp.Response == nil || len(errResp.Error()) == 0 {
+		t.Errorf("unexpected error response %+v", errResp)
+	}
+}
+
+func TestSendCommonEmailResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("respEmailId") != "true" {
+			t.Errorf("respEmailId was not sent")
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"emailIdList":["1@sendcloud.net","2@sendcloud.net"]}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com;b@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:        "SendCloud@SendCloud.com",
+			Subject:     "Email from SendCloud SDK",
+			RespEmailID: true,
+		},
+		Content: sendcloud.TextContent{
+			Html: "<p>This is an HTML email.</p>",
+		},
+	}
+	result, err := client.SendCommonEmail(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Info.EmailIDs) != 2 || result.Info.EmailIDs[1] != "2@sendcloud.net" {
+		t.Errorf("unexpected email ids %v", result.Info.EmailIDs)
+	}
+	if len(result.RawInfo) == 0 {
+		t.Error("raw info was not kept")
+	}
+}
+
+func TestSendCommonEmailWithReaderAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		files := r.MultipartForm.File["attachments"]
+		if len(files) != 3 {
+			t.Errorf("expected 3 attachments, got %d", len(files))
+			http.Error(w, "missing attachments", http.StatusBadRequest)
+			return
+		}
+		expected := []struct{ filename, contentType, content string }{
+			{"invoice.json", "application/json", `{"id":1,"amount":100}`},
+			{"logo.txt", "text/plain; charset=utf-8", "logo"},
+			{"report", "text/plain; charset=utf-8", "plain report"},
+		}
+		for i, file := range files {
+			f, _ := file.Open()
+			content, _ := io.ReadAll(f)
+			f.Close()
+			if file.Filename != expected[i].filename || file.Header.Get("Content-Type") != expected[i].contentType ||
+				string(content) != expected[i].content {
+				t.Errorf("unexpected attachment %s %s %q", file.Filename, file.Header.Get("Content-Type"), content)
+			}
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsAttachment, err := sendcloud.NewFSAttachment(fstest.MapFS{
+		"static/logo.txt": {Data: []byte("logo")},
+	}, "static/logo.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.CreateTemp("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	file.WriteString("plain report")
+	file.Seek(0, io.SeekStart)
+	fileAttachment := sendcloud.NewFileAttachment(file)
+	fileAttachment.Filename = "report"
+
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:    "SendCloud@SendCloud.com",
+			Subject: "Email from SendCloud SDK",
+		},
+		Content: sendcloud.TextContent{
+			Html: "<p>This is an HTML email.</p>",
+		},
+	}
+	args.Body.AddAttachments(
+		sendcloud.NewBytesAttachment("invoice.json", []byte(`{"id":1,"amount":100}`)),
+		fsAttachment,
+		fileAttachment,
+	)
+	if _, err := client.SendCommonEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Stat(); err != nil {
+		t.Errorf("caller-owned file was closed: %v", err)
+	}
+}
+
+func TestSendTemplateEmailStreamsAttachments(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := atomic.AddInt32(&attempts, 1)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unexpected body: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if attempt == 1 {
+			// Sizes are known, so the body is sent with an exact Content-Length.
+			if r.ContentLength != int64(len(body)) {
+				t.Errorf("content length %d does not match body size %d", r.ContentLength, len(body))
+			}
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if !strings.Contains(string(body), strings.Repeat("x", 4096)) {
+			t.Error("attachment was not streamed again on retry")
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithRetry(sendcloud.RetryPolicy{
+			MaxAttempts:     2,
+			RetryableStatus: []int{http.StatusBadGateway},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.TemplateMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:          "SendCloud@SendCloud.com",
+			Subject:       "Email from SendCloud SDK",
+			SendRequestID: "stream-1",
+		},
+		TemplateInvokeName: "test_template_active",
+	}
+	args.Body.AddAttachments(sendcloud.NewAttachment("big.txt", strings.NewReader(strings.Repeat("x", 4096))))
+	if _, err := client.SendTemplateEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+
+	// A reader of unknown size is sent chunked.
+	atomic.StoreInt32(&attempts, 1)
+	args.Body.Attachments = nil
+	args.Body.AddAttachments(sendcloud.NewAttachment("big.txt", io.MultiReader(strings.NewReader(strings.Repeat("x", 4096)))))
+	if _, err := client.SendTemplateEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSendTemplateEmailRetryCanceledDoesNotLeak(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	httpClient := server.Client()
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithHTTPClient(httpClient),
+		sendcloud.WithRetry(sendcloud.RetryPolicy{
+			MaxAttempts:     3,
+			BaseDelay:       time.Hour,
+			RetryableStatus: []int{http.StatusServiceUnavailable},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := runtime.NumGoroutine()
+	for i := 0; i < 5; i++ {
+		args := &sendcloud.TemplateMail{
+			Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+			Body: sendcloud.MailBody{
+				From:          "SendCloud@SendCloud.com",
+				Subject:       "Email from SendCloud SDK",
+				SendRequestID: fmt.Sprintf("leak-%d", i),
+			},
+			TemplateInvokeName: "test_template_active",
+		}
+		args.Body.AddAttachments(sendcloud.NewAttachment("big.txt", strings.NewReader(strings.Repeat("x", 1<<20))))
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := client.SendTemplateEmail(ctx, args)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected the backoff to end with the context, got %v", err)
+		}
+	}
+	httpClient.CloseIdleConnections()
+	server.CloseClientConnections()
+	// The body of the retry must not have started a writer that nobody reads.
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines before the sends, %d after", before, after)
+	}
+}
+
+func TestSendCommonEmailAttachmentTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent")
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:    "SendCloud@SendCloud.com",
+			Subject: "Email from SendCloud SDK",
+		},
+		Content: sendcloud.TextContent{
+			Html: "<p>This is an HTML email.</p>",
+		},
+	}
+	args.Body.AddAttachments(sendcloud.NewBytesAttachment("huge.bin", make([]byte, sendcloud.MAX_ATTACHMENT_SIZE+1)))
+	_, err = client.SendCommonEmail(context.Background(), args)
+	var sizeErr *sendcloud.AttachmentSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Size != sendcloud.MAX_ATTACHMENT_SIZE+1 {
+		t.Fatalf("expected attachment size error, got %v", err)
+	}
+}
+
+func TestSendCommonEmailUnknownSizeAttachmentTooLarge(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithRetry(sendcloud.RetryPolicy{MaxAttempts: 3}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:          "SendCloud@SendCloud.com",
+			Subject:       "Email from SendCloud SDK",
+			SendRequestID: "too-large-1",
+		},
+		Content: sendcloud.TextContent{
+			Html: "<p>This is an HTML email.</p>",
+		},
+	}
+	// The known size fits, the reader of unknown size pushes the total past the limit.
+	args.Body.AddAttachments(
+		sendcloud.NewBytesAttachment("known.bin", make([]byte, sendcloud.MAX_ATTACHMENT_SIZE-10)),
+		sendcloud.NewAttachment("stream.bin", io.MultiReader(bytes.NewReader(make([]byte, 20)))),
+	)
+	_, err = client.SendCommonEmail(context.Background(), args)
+	var sizeErr *sendcloud.AttachmentSizeError
+	if !errors.As(err, &sizeErr) || sizeErr.Size <= sendcloud.MAX_ATTACHMENT_SIZE {
+		t.Fatalf("expected attachment size error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n > 1 {
+		t.Errorf("expected the upload not to be retried, got %d attempts", n)
+	}
+}
+
+// unsizedReader hides the size of a *bytes.Reader while keeping it seekable.
+type unsizedReader struct {
+	io.ReadSeeker
+}
+
+func TestSendCommonEmailAttachmentTooLargeIsNotRetried(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	// A retry would wait for an hour and take a second token.
+	client, err := sendcloud.NewSendCloud("*", "*",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithRetry(sendcloud.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}),
+		sendcloud.WithRateLimit(sendcloud.ENDPOINT_SEND_COMMON, 1000, 10),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+		Body:     sendcloud.MailBody{From: "SendCloud@SendCloud.com", Subject: "Hi", SendRequestID: "too-large-2"},
+		Content:  sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+	// The reader can be rewound for a retry, but its size is only known once read.
+	args.Body.AddAttachments(sendcloud.NewAttachment("stream.bin", unsizedReader{bytes.NewReader(make([]byte, sendcloud.MAX_ATTACHMENT_SIZE+1))}))
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = client.SendCommonEmail(ctx, args)
+	var sizeErr *sendcloud.AttachmentSizeError
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("expected attachment size error, got %v", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n > 1 {
+		t.Errorf("expected a single attempt, got %d", n)
+	}
+	if stats := client.RateLimiter(sendcloud.ENDPOINT_SEND_COMMON).Stats(); stats.Requests != 1 {
+		t.Errorf("expected a single rate limit token, got %+v", stats)
+	}
+}
+
+func TestSendCommonEmailWithInlineImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if cid := r.MultipartForm.Value["embeddedCid"]; len(cid) != 1 || cid[0] != "image1" {
+			t.Errorf("unexpected embeddedCid %v", cid)
+		}
+		if images := r.MultipartForm.File["embeddedImage"]; len(images) != 1 || images[0].Filename != "logo.png" {
+			t.Errorf("unexpected embedded images %v", images)
+		}
+		if html := r.MultipartForm.Value["html"]; len(html) != 1 || html[0] != `<p><img alt="logo" src="cid:image1"><img src='cid:image1'></p>` {
+			t.Errorf("unexpected html %v", html)
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "logo.png")
+	if err := os.WriteFile(imagePath, []byte("\x89PNG\r\n\x1a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	html, inlineAttachments, err := sendcloud.EmbedLocalImages(
+		`<p><img alt="logo" src="file://` + filepath.ToSlash(imagePath) + `"><img src='file://` + filepath.ToSlash(imagePath) + `'></p>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:              "SendCloud@SendCloud.com",
+			Subject:           "Email from SendCloud SDK",
+			InlineAttachments: inlineAttachments,
+		},
+		Content: sendcloud.TextContent{
+			Html: html,
+		},
+	}
+	if _, err := client.SendCommonEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmbedLocalImagesRejectsHost(t *testing.T) {
+	dir := t.TempDir()
+	imagePath := filepath.Join(dir, "logo.png")
+	if err := os.WriteFile(imagePath, []byte("\x89PNG\r\n\x1a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// The path after the host exists, but the host makes the source ambiguous.
+	for _, src := range []string{"file://images" + filepath.ToSlash(imagePath), "file://example.com" + filepath.ToSlash(imagePath)} {
+		if _, _, err := sendcloud.EmbedLocalImages(`<img src="` + src + `">`); err == nil {
+			t.Errorf("expected error for %s", src)
+		}
+	}
+	_, inlineAttachments, err := sendcloud.EmbedLocalImages(`<img src="file://localhost` + filepath.ToSlash(imagePath) + `">`)
+	if err != nil || len(inlineAttachments) != 1 {
+		t.Errorf("expected localhost to be accepted, got %v", err)
+	}
+}
+
+func TestSendCommonEmailWithNilInlineAttachment(t *testing.T) {
+	client, err := sendcloud.NewSendCloud("*", "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{
+			To: "a@ifaxin.com",
+		},
+		Body: sendcloud.MailBody{
+			From:    "SendCloud@SendCloud.com",
+			Subject: "Email from SendCloud SDK",
+		},
+		Content: sendcloud.TextContent{
+			Html: `<img src="cid:logo">`,
+		},
+	}
+	args.Body.AddInlineAttachment("logo", nil)
+	if _, err := client.SendCommonEmail(context.Background(), args); err == nil {
+		t.Error("expected error for nil inline attachment")
+	}
+	recipients := []sendcloud.Recipient{{Address: "a@ifaxin.com"}}
+	args.Receiver.To = ""
+	if _, err := client.SendCommonEmailBatch(context.Background(), args, recipients, nil); err == nil {
+		t.Error("expected error for nil inline attachment in a batch")
+	}
+}
+
+func TestXSMTPAPIBuilder(t *testing.T) {
+	xsmtpapi, err := sendcloud.NewXSMTPAPIBuilder().
+		AddRecipient("a@ifaxin.com", map[string]interface{}{"name": "jack", "%money%": "199"}).
+		AddRecipient("b@ifaxin.com", map[string]interface{}{"name": "rose"}).
+		AddRecipient("c@ifaxin.com", map[string]interface{}{"coupon": "X1"}).
+		SetPubsub("year", 2024).
+		SetFilters(true, true, false).
+		SetUnsubscribePages(1, 2).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string][]interface{}{
+		"%name%":   {"jack", "rose", ""},
+		"%money%":  {"199", "", ""},
+		"%coupon%": {"", "", "X1"},
+	}
+	if !reflect.DeepEqual(xsmtpapi.Sub, expected) {
+		t.Errorf("unexpected sub %v", xsmtpapi.Sub)
+	}
+	if !reflect.DeepEqual(xsmtpapi.To, []string{"a@ifaxin.com", "b@ifaxin.com", "c@ifaxin.com"}) {
+		t.Errorf("unexpected to %v", xsmtpapi.To)
+	}
+	if xsmtpapi.Pubsub["%year%"] != 2024 || xsmtpapi.Filters.ClickTracking.Settings.Enable != "0" {
+		t.Errorf("unexpected pubsub or filters %v %v", xsmtpapi.Pubsub, xsmtpapi.Filters)
+	}
+
+	_, err = sendcloud.NewXSMTPAPIBuilder().
+		AddRecipient("a@ifaxin.com", nil).
+		AddRecipient("a@ifaxin.com", nil).
+		Build()
+	if err == nil {
+		t.Error("expected error for duplicate recipient")
+	}
+}
+
+func TestSendTemplateEmailBatch(t *testing.T) {
+	var sent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		atomic.AddInt32(&sent, 1)
+		if strings.Contains(r.PostForm.Get("xsmtpapi"), "fail@ifaxin.com") {
+			w.Write([]byte(`{"result":false,"statusCode":40011,"message":"invalid to","info":{}}`))
+			return
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recipients []sendcloud.Recipient
+	for i := 0; i < 250; i++ {
+		recipients = append(recipients, sendcloud.Recipient{
+			Address: fmt.Sprintf("user%d@ifaxin.com", i),
+			Vars:    map[string]interface{}{"name": fmt.Sprintf("user%d", i)},
+		})
+	}
+	recipients = append(recipients, sendcloud.Recipient{Address: "fail@ifaxin.com"})
+	args := &sendcloud.TemplateMail{
+		Body: sendcloud.MailBody{
+			From:          "SendCloud@SendCloud.com",
+			Subject:       "Email from SendCloud SDK",
+			SendRequestID: "campaign",
+		},
+		TemplateInvokeName: "test_template_active",
+	}
+	result, err := client.SendTemplateEmailBatch(context.Background(), args, recipients, &sendcloud.BatchOptions{Concurrency: 2})
+	var batchErr *sendcloud.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 1 || batchErr.Total != 3 {
+		t.Fatalf("expected 1 of 3 batches to fail, got %v", err)
+	}
+	if sent != 3 || len(result.Batches) != 3 || len(result.Batches[0].Recipients) != sendcloud.MAX_RECEIVERS {
+		t.Fatalf("unexpected batches %d %v", sent, result.Batches)
+	}
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Index != 2 || !sendcloud.IsInvalidRecipient(failed[0].Err) {
+		t.Errorf("unexpected failed batches %v", failed)
+	}
+}
+
+func TestSendEmailBatchInvalidRecipients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent")
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recipients []sendcloud.Recipient
+	for i := 0; i < 150; i++ {
+		recipients = append(recipients, sendcloud.Recipient{Address: fmt.Sprintf("user%d@ifaxin.com", i)})
+	}
+	args := &sendcloud.TemplateMail{
+		Body: sendcloud.MailBody{
+			From:    "SendCloud@SendCloud.com",
+			Subject: "Email from SendCloud SDK",
+		},
+		TemplateInvokeName: "test_template_active",
+	}
+	// The duplicate lands in another batch than the first occurrence.
+	duplicated := append(recipients, sendcloud.Recipient{Address: " user1@ifaxin.com"})
+	if _, err := client.SendTemplateEmailBatch(context.Background(), args, duplicated, nil); err == nil {
+		t.Error("expected error for a recipient repeated across batches")
+	}
+	args.Body.Xsmtpapi.To = []string{"a@ifaxin.com"}
+	if _, err := client.SendTemplateEmailBatch(context.Background(), args, recipients, nil); err == nil {
+		t.Error("expected error for xsmtpapi to in a batch send")
+	}
+	common := &sendcloud.CommonMail{
+		Body:    args.Body,
+		Content: sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+	common.Body.Xsmtpapi = sendcloud.XSMTPAPI{Sub: map[string][]interface{}{"%name%": {"a"}}}
+	if _, err := client.SendCommonEmailBatch(context.Background(), common, recipients, nil); err == nil {
+		t.Error("expected error for xsmtpapi sub in a batch send")
+	}
+}
+
+func TestSendTemplateEmailBatchAttachments(t *testing.T) {
+	var sent int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		expected := []string{"report content", "streamed content"}
+		files := r.MultipartForm.File["attachments"]
+		if len(files) != len(expected) {
+			t.Errorf("expected %d attachments, got %d", len(expected), len(files))
+			http.Error(w, "missing attachments", http.StatusBadRequest)
+			return
+		}
+		for i, file := range files {
+			f, _ := file.Open()
+			content, _ := io.ReadAll(f)
+			f.Close()
+			if string(content) != expected[i] {
+				t.Errorf("unexpected content %q of %s", content, file.Filename)
+			}
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := os.WriteFile(path, []byte("# report content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// Only the content after the current offset is sent.
+	if _, err := file.Seek(2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recipients []sendcloud.Recipient
+	for i := 0; i < 250; i++ {
+		recipients = append(recipients, sendcloud.Recipient{Address: fmt.Sprintf("user%d@ifaxin.com", i)})
+	}
+	args := &sendcloud.TemplateMail{
+		Body: sendcloud.MailBody{
+			From:    "SendCloud@SendCloud.com",
+			Subject: "Email from SendCloud SDK",
+		},
+		TemplateInvokeName: "test_template_active",
+	}
+	args.Body.AddAttachments(
+		sendcloud.NewAttachment("report.txt", file),
+		sendcloud.NewAttachment("stream.txt", io.MultiReader(strings.NewReader("streamed content"))),
+	)
+	if _, err := client.SendTemplateEmailBatch(context.Background(), args, recipients, &sendcloud.BatchOptions{Concurrency: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 3 {
+		t.Fatalf("expected 3 batches, got %d", sent)
+	}
+	// The file was shared through section readers, its offset is unchanged.
+	if offset, _ := file.Seek(0, io.SeekCurrent); offset != 2 {
+		t.Errorf("unexpected file offset %d", offset)
+	}
+}
+
+func TestSendCommonEmailRateLimit(t *testing.T) {
+	var sends int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&sends, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithRetry(sendcloud.DefaultRetryPolicy),
+		sendcloud.WithRateLimit(sendcloud.ENDPOINT_SEND_COMMON, 1000, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CommonMail{
+		Receiver: sendcloud.MailReceiver{To: "a@ifaxin.com"},
+		Body:     sendcloud.MailBody{From: "SendCloud@SendCloud.com", Subject: "Hi", SendRequestID: "req-1"},
+		Content:  sendcloud.TextContent{Html: "<p>Hi</p>"},
+	}
+	if _, err := client.SendCommonEmail(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	// The retry after the 429 goes through the limiter as well.
+	stats := client.RateLimiter(sendcloud.ENDPOINT_SEND_COMMON).Stats()
+	if stats.Requests != 2 || atomic.LoadInt32(&sends) != 2 {
+		t.Errorf("unexpected stats %+v after %d sends", stats, sends)
+	}
+	if client.RateLimiter(sendcloud.ENDPOINT_SEND_TEMPLATE) != nil {
+		t.Error("expected no limiter on the template endpoint")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	client, err := sendcloud.NewSendCloud("*", "*", sendcloud.WithRateLimit(sendcloud.ENDPOINT_SEND_COMMON, 5, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := client.RateLimiter(sendcloud.ENDPOINT_SEND_COMMON)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+	// The canceled wait gave its token back, so the next one waits about 200ms.
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("expected a wait of about 200ms, took %v", elapsed)
+	}
+	stats := limiter.Stats()
+	if stats.Requests != 3 || stats.Waits != 2 || stats.WaitTime <= 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,362 @@
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/sendcloud2013/sendcloud-sdk-go/sms"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// smsSignature signs form the way SendCloud signs SMS requests.
+func smsSignature(smsKey string, form url.Values) string {
+	var pairs []string
+	for key := range form {
+		if key != "signature" {
+			pairs = append(pairs, key+"="+form.Get(key))
+		}
+	}
+	sort.Strings(pairs)
+	sum := sha256.Sum256([]byte(smsKey + "&" + strings.Join(pairs, "&") + "&" + smsKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSmsManagementServer serves the given JSON info for each path, checks the
+// signature of every request and records the form of the last request to every path.
+func newSmsManagementServer(t *testing.T, smsKey string, infos map[string]string) (*httptest.Server, map[string]url.Values) {
+	forms := make(map[string]url.Values)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		info, ok := infos[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.PostForm.Get("smsUser") == "" || r.PostForm.Get("signature") != smsSignature(smsKey, r.PostForm) {
+			t.Errorf("invalid signature for %s", r.URL.Path)
+		}
+		forms[r.URL.Path] = r.PostForm
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":` + info + `}`))
+	}))
+	return server, forms
+}
+
+func TestSmsTemplates(t *testing.T) {
+	server, forms := newSmsManagementServer(t, "key", map[string]string{
+		"/template/list":   `{"total":1,"dataList":[{"templateId":12,"templateName":"otp","templateContent":"Your code is %code%","auditStatus":1}]}`,
+		"/template/get":    `{"data":{"templateId":12,"templateName":"otp","auditStatus":2,"auditReason":"missing sign"}}`,
+		"/template/save":   `{"data":{"templateId":13,"templateName":"otp_fr","auditStatus":0}}`,
+		"/template/update": `{}`,
+		"/template/delete": `{}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	approved := sendcloud.AUDIT_APPROVED
+	list, err := client.Templates.List(ctx, &sendcloud.SmsTemplateListOptions{AuditStatus: &approved, Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 1 || !list.Templates[0].Approved() || forms["/template/list"].Get("auditStatus") != "1" {
+		t.Errorf("unexpected list %+v", list)
+	}
+
+	template, err := client.Templates.Get(ctx, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template.AuditStatus != sendcloud.AUDIT_REJECTED || template.AuditReason != "missing sign" {
+		t.Errorf("unexpected template %+v", template)
+	}
+
+	created, err := client.Templates.Create(ctx, &sendcloud.SmsTemplate{
+		TemplateName: "otp_fr",
+		Content:      "Votre code est %code%",
+		MsgType:      sendcloud.SMS,
+		SmsType:      sendcloud.SMS_TYPE_CODE,
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.TemplateID != 13 || forms["/template/save"].Get("isSubmitAudit") != "true" {
+		t.Errorf("unexpected created template %+v", created)
+	}
+	created.Content = "Code : %code%"
+	if err := client.Templates.Update(ctx, created, false); err != nil {
+		t.Fatal(err)
+	}
+	if forms["/template/update"].Get("templateId") != "13" {
+		t.Errorf("unexpected update params %v", forms["/template/update"])
+	}
+	if err := client.Templates.Delete(ctx, 13); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Templates.Create(ctx, &sendcloud.SmsTemplate{TemplateName: "empty"}, false); err == nil {
+		t.Error("expected validation error")
+	}
+}
+
+func TestSmsTemplatesInvalidID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := client.Templates.Get(ctx, 0); err == nil {
+		t.Error("expected error for templateId 0")
+	}
+	if err := client.Templates.Delete(ctx, -1); err == nil {
+		t.Error("expected error for templateId -1")
+	}
+	if err := client.Templates.Submit(ctx, 0); err == nil {
+		t.Error("expected error for templateId 0")
+	}
+}
+
+func TestSmsSigns(t *testing.T) {
+	server, forms := newSmsManagementServer(t, "key", map[string]string{
+		"/sign/list": `{"total":2,"dataList":[{"signId":1,"signName":"SendCloud","auditStatus":1},{"signId":2,"signName":"NewBrand","auditStatus":0}]}`,
+		"/sign/save": `{"data":{"signId":3,"signName":"Other","auditStatus":0}}`,
+		"/sendCode":  `{"successCount":1,"smsIds":["1"]}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL), sendcloud.WithSignCheck(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	sign, err := client.Signs.Create(ctx, "Other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sign.SignID != 3 || forms["/sign/save"].Get("signName") != "Other" {
+		t.Errorf("unexpected sign %+v", sign)
+	}
+
+	args := &sendcloud.CodeSms{MsgType: sendcloud.SMS, Phone: "13800138000", Code: "123456", SignName: "SendCloud"}
+	if _, err := client.SendCodeSmsWithContext(ctx, args); err != nil {
+		t.Fatal(err)
+	}
+	args.SignName = "NewBrand"
+	if _, err := client.SendCodeSmsWithContext(ctx, args); !errors.Is(err, sendcloud.ErrSignNotApproved) {
+		t.Errorf("expected ErrSignNotApproved, got %v", err)
+	}
+	args.SignName = ""
+	args.SignId = 9
+	if _, err := client.SendCodeSmsWithContext(ctx, args); !errors.Is(err, sendcloud.ErrSignNotFound) {
+		t.Errorf("expected ErrSignNotFound, got %v", err)
+	}
+}
+
+func TestSmsSignsInvalidID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.Path)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := client.Signs.Get(ctx, 0); err == nil {
+		t.Error("expected error for id 0")
+	}
+	if err := client.Signs.Update(ctx, 0, "name"); err == nil {
+		t.Error("expected error for id 0")
+	}
+	if err := client.Signs.Delete(ctx, -1); err == nil {
+		t.Error("expected error for id -1")
+	}
+}
+
+func TestSmsSignCheckRefresh(t *testing.T) {
+	var lists, failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sign/list":
+			atomic.AddInt32(&lists, 1)
+			if atomic.LoadInt32(&failing) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"total":1,"dataList":[{"signId":1,"signName":"SendCloud","auditStatus":1}]}}`))
+		case "/sign/save":
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"data":{"signId":3,"signName":"Other","auditStatus":0}}}`))
+		default:
+			w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"successCount":1}}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL), sendcloud.WithSignCheck(20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	args := &sendcloud.CodeSms{MsgType: sendcloud.SMS, Phone: "13800138000", Code: "123456", SignName: "SendCloud"}
+	if _, err := client.SendCodeSmsWithContext(ctx, args); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the list expired, a failed lookup falls back to the last good list.
+	atomic.StoreInt32(&failing, 1)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.SendCodeSmsWithContext(ctx, args); err != nil {
+		t.Errorf("expected the last good list to be used, got %v", err)
+	}
+	if n := atomic.LoadInt32(&lists); n != 2 {
+		t.Errorf("expected 2 sign lookups, got %d", n)
+	}
+
+	// Without a list, the lookup error is returned.
+	if _, err := client.Signs.Create(ctx, "Other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SendCodeSmsWithContext(ctx, args); err == nil {
+		t.Error("expected the lookup error")
+	}
+}
+
+func TestQuerySmsStatus(t *testing.T) {
+	var forms []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("unexpected form: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/data/status" || r.PostForm.Get("signature") != smsSignature("key", r.PostForm) {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		forms = append(forms, r.PostForm)
+		start, _ := strconv.Atoi(r.PostForm.Get("start"))
+		limit, _ := strconv.Atoi(r.PostForm.Get("limit"))
+		var rows []string
+		for i := start; i < start+limit && i < 3; i++ {
+			rows = append(rows, fmt.Sprintf(`{"smsId":"%d","phone":"13800138000","status":"failed","errorCode":"MK:000%d"}`, i, i))
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"total":3,"dataList":[` + strings.Join(rows, ",") + `]}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	it := client.IterateSmsStatus(context.Background(), &sendcloud.SmsStatusQuery{Phone: "13800138000", TemplateID: 12, StartDate: day, Limit: 2})
+	var codes []string
+	for it.Next() {
+		if it.Record().Status == sendcloud.SMS_STATUS_FAILED {
+			codes = append(codes, it.Record().ErrorCode)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 3 || codes[2] != "MK:0002" || len(forms) != 2 {
+		t.Errorf("unexpected codes %v in %d pages", codes, len(forms))
+	}
+	if forms[0].Get("templateId") != "12" || forms[0].Get("startDate") != "2024-01-02" || forms[0].Get("endDate") != "2024-01-02" {
+		t.Errorf("unexpected params %v", forms[0])
+	}
+	if _, err := client.QuerySmsStatus(context.Background(), &sendcloud.SmsStatusQuery{Phone: "13800138000"}); err == nil {
+		t.Error("expected error without startDate")
+	}
+}
+
+func TestSmsStats(t *testing.T) {
+	server, forms := newSmsManagementServer(t, "key", map[string]string{
+		"/statday/list": `{"dataList":[{"sendDate":"2024-01-02","templateId":"12","requestNum":"200","deliveredNum":"190","failedNum":"6","unknownNum":"4","chargeNum":"210","fee":"10.50"}]}`,
+		"/statsum/list": `{"dataList":[{"msgType":0,"requestNum":500,"deliveredNum":400},{"msgType":3,"requestNum":0}]}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	daily, err := client.GetDailySmsStats(ctx, &sendcloud.SmsStatsQuery{
+		StartDate: day,
+		EndDate:   day.AddDate(0, 0, 30),
+		LabelIDs:  []int{3, 4},
+		GroupBy:   []string{sendcloud.STATS_GROUP_TEMPLATE},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily) != 1 || daily[0].TemplateID != 12 || daily[0].Billed != 210 || daily[0].Cost != 10.5 || daily[0].SuccessRate != 0.95 {
+		t.Errorf("unexpected daily stats %+v", daily)
+	}
+	form := forms["/statday/list"]
+	if form.Get("endDate") != "2024-02-01" || form.Get("labelIdList") != "3,4" || form.Get("groupBy") != "templateId" {
+		t.Errorf("unexpected params %v", form)
+	}
+
+	summary, err := client.GetSmsStatsSummary(ctx, &sendcloud.SmsStatsQuery{StartDate: day, GroupBy: []string{sendcloud.STATS_GROUP_MSG_TYPE}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary) != 2 || summary[0].SuccessRate != 0.8 || summary[1].MsgType != sendcloud.VOICE || summary[1].SuccessRate != 0 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if _, err := client.GetSmsStatsSummary(ctx, &sendcloud.SmsStatsQuery{StartDate: day, GroupBy: []string{"phone"}}); err == nil {
+		t.Error("expected error for illegal grouping")
+	}
+}
+
+func TestSmsQuota(t *testing.T) {
+	server, _ := newSmsManagementServer(t, "key", map[string]string{
+		"/userinfo/get": `{"quota":5000,"usedQuota":4990,"balance":"3.20","availableBalance":"3.20"}`,
+	})
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("user", "key", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	quota, err := client.GetQuota(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quota.Remaining() != 10 {
+		t.Errorf("unexpected quota %+v", quota)
+	}
+	balance, err := client.GetBalance(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 3.2 {
+		t.Errorf("unexpected balance %+v", balance)
+	}
+}
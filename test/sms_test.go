@@ -1,8 +1,14 @@
 package test
 
 import (
+	"context"
+	"errors"
 	"github.com/sendcloud2013/sendcloud-sdk-go/sms"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestSendTemplateSms(t *testing.T) {
@@ -65,3 +71,165 @@ func TestSendCodeSms(t *testing.T) {
 	}
 	t.Log(result)
 }
+
+func TestSendCodeSmsWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sendCode" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("**", "**",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithHTTPClient(server.Client()),
+		sendcloud.WithTimeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.SendCodeSmsWithContext(context.Background(), &sendcloud.CodeSms{
+		Code:  "123456",
+		Phone: "13800138000",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(result)
+}
+
+func TestSendTemplateSmsCanceled(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client, err := sendcloud.NewSendCloudSms("**", "**", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.SendTemplateSmsWithContext(ctx, &sendcloud.TemplateSms{
+		TemplateId: 1,
+		Phone:      "13800138000",
+		MsgType:    sendcloud.SMS,
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestSendVoiceSmsRetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("**", "**",
+		sendcloud.WithBaseURL(server.URL),
+		sendcloud.WithRetry(sendcloud.DefaultRetryPolicy),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.SendVoiceSms(&sendcloud.VoiceSms{
+		Code:          "123456",
+		Phone:         "13800138000",
+		SendRequestId: "voice-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestSendTemplateSmsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"result":false,"statusCode":429,"message":"too many requests"}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("**", "**", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.SendTemplateSms(&sendcloud.TemplateSms{
+		TemplateId: 1,
+		Phone:      "13800138000",
+		MsgType:    sendcloud.SMS,
+	})
+	if !errors.Is(err, sendcloud.ErrRateLimited) {
+		t.Fatalf("expected rate limited error, got %v", err)
+	}
+	var apiErr *sendcloud.APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusTooManyRequests {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var errResp *sendcloud.ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("expected *APIError to match *ErrorResponse, got %v", err)
+	}
+	if errResp.Message != "too many requests" || errResp.Response.StatusCode != http.StatusTooManyRequests || len(errResp.Error()) == 0 {
+		t.Errorf("unexpected error response %+v", errResp)
+	}
+}
+
+func TestSendTemplateSmsResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"successCount":2,"smsIds":["a$13800138000","b$13800138001"]}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("**", "**", sendcloud.WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.SendTemplateSms(&sendcloud.TemplateSms{
+		TemplateId: 1,
+		Phone:      "13800138000,13800138001",
+		MsgType:    sendcloud.SMS,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Info.SuccessCount != 2 || len(result.Info.SmsIDs) != 2 {
+		t.Errorf("unexpected info %+v", result.Info)
+	}
+}
+
+func TestSendCodeSmsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":true,"statusCode":200,"message":"ok","info":{"successCount":1}}`))
+	}))
+	defer server.Close()
+
+	client, err := sendcloud.NewSendCloudSms("**", "**", sendcloud.WithBaseURL(server.URL), sendcloud.WithRateLimit(sendcloud.ENDPOINT_SEND_CODE, 10, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &sendcloud.CodeSms{MsgType: sendcloud.SMS, Phone: "13800138000", Code: "123456"}
+	for i := 0; i < 3; i++ {
+		if _, err := client.SendCodeSms(args); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := client.RateLimiter(sendcloud.ENDPOINT_SEND_CODE).Stats(); stats.Requests != 3 || stats.Waits != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if _, err := sendcloud.NewSendCloudSms("**", "**", sendcloud.WithRateLimit("sendMail", 10, 1)); err == nil {
+		t.Error("expected error for unknown endpoint")
+	}
+}
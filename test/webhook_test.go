@@ -0,0 +1,155 @@
+package test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/sendcloud2013/sendcloud-sdk-go/email/webhook"
+	"github.com/sendcloud2013/sendcloud-sdk-go/sms"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func postEvent(handler http.Handler, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/webhook", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func signedEvent(appKey string, token string, sentAt time.Time, fields map[string]string) url.Values {
+	timestamp := strconv.FormatInt(sentAt.UnixNano()/int64(time.Millisecond), 10)
+	mac := hmac.New(sha256.New, []byte(appKey))
+	mac.Write([]byte(timestamp + token))
+	form := url.Values{}
+	form.Set("timestamp", timestamp)
+	form.Set("token", token)
+	form.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+	for key, value := range fields {
+		form.Set(key, value)
+	}
+	return form
+}
+
+func TestEmailWebhook(t *testing.T) {
+	handler, err := webhook.NewHandler("app-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clicks []*webhook.ClickEvent
+	handler.OnClick(func(event *webhook.ClickEvent) error {
+		clicks = append(clicks, event)
+		return nil
+	})
+	var bounces []*webhook.BounceEvent
+	handler.OnBounce(func(event *webhook.BounceEvent) error {
+		bounces = append(bounces, event)
+		if len(bounces) == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+
+	click := signedEvent("app-key", "token-1", time.Now(), map[string]string{
+		"event": "click", "emailId": "1@ifaxin.com", "recipient": "a@ifaxin.com", "labelId": "7", "url": "https://example.com/offer",
+	})
+	if rec := postEvent(handler, click); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", rec.Code, rec.Body)
+	}
+	if len(clicks) != 1 || clicks[0].URL != "https://example.com/offer" || clicks[0].LabelID != 7 || clicks[0].Recipient != "a@ifaxin.com" {
+		t.Errorf("unexpected clicks %+v", clicks)
+	}
+	if rec := postEvent(handler, click); rec.Code != http.StatusForbidden {
+		t.Errorf("expected replay to be rejected, got %d", rec.Code)
+	}
+
+	bounce := signedEvent("app-key", "token-2", time.Now(), map[string]string{"event": "soft_bounce", "subStat": "401"})
+	if rec := postEvent(handler, bounce); rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected callback error to be reported, got %d", rec.Code)
+	}
+	if rec := postEvent(handler, bounce); rec.Code != http.StatusOK {
+		t.Errorf("expected failed event to be accepted again, got %d", rec.Code)
+	}
+	if len(bounces) != 2 || !bounces[1].Soft() || bounces[1].SubStat != 401 {
+		t.Errorf("unexpected bounces %+v", bounces)
+	}
+
+	forged := signedEvent("other-key", "token-3", time.Now(), map[string]string{"event": "click"})
+	if rec := postEvent(handler, forged); rec.Code != http.StatusForbidden {
+		t.Errorf("expected forged event to be rejected, got %d", rec.Code)
+	}
+	stale := signedEvent("app-key", "token-4", time.Now().Add(-time.Hour), map[string]string{"event": "click"})
+	if rec := postEvent(handler, stale); rec.Code != http.StatusForbidden {
+		t.Errorf("expected stale event to be rejected, got %d", rec.Code)
+	}
+	open := signedEvent("app-key", "token-5", time.Now(), map[string]string{"event": "open"})
+	if rec := postEvent(handler, open); rec.Code != http.StatusOK {
+		t.Errorf("expected event without callback to be acknowledged, got %d", rec.Code)
+	}
+	if len(clicks) != 1 {
+		t.Errorf("unexpected clicks %+v", clicks)
+	}
+}
+
+func signedSmsCallback(smsKey string, fields map[string]string) url.Values {
+	form := url.Values{}
+	for key, value := range fields {
+		form.Set(key, value)
+	}
+	form.Set("signature", smsSignature(smsKey, form))
+	return form
+}
+
+func TestSmsWebhook(t *testing.T) {
+	handler, err := sendcloud.NewWebhookHandler("sms-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed []*sendcloud.FailedEvent
+	handler.OnFailed(func(event *sendcloud.FailedEvent) error {
+		failed = append(failed, event)
+		return nil
+	})
+	var replies []*sendcloud.ReplyEvent
+	handler.OnReply(func(event *sendcloud.ReplyEvent) error {
+		replies = append(replies, event)
+		return nil
+	})
+
+	timestamp := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+	failure := signedSmsCallback("sms-key", map[string]string{
+		"event": "delivererror", "smsId": "1700000000000_1_1", "phone": "13800138000", "errorCode": "MK:0001",
+		"templateId": "12", "tag": `{"orderId":"42"}`, "timestamp": timestamp,
+	})
+	if rec := postEvent(handler, failure); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", rec.Code, rec.Body)
+	}
+	if len(failed) != 1 || failed[0].ErrorCode != "MK:0001" || failed[0].Tag["orderId"] != "42" || failed[0].TemplateID != 12 {
+		t.Errorf("unexpected failed events %+v", failed)
+	}
+
+	reply := signedSmsCallback("sms-key", map[string]string{
+		"event": "reply", "phone": "13800138000", "content": "STOP", "timestamp": timestamp, "replyTime": timestamp,
+	})
+	if rec := postEvent(handler, reply); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", rec.Code, rec.Body)
+	}
+	if len(replies) != 1 || replies[0].Content != "STOP" || replies[0].ReplyTime.IsZero() {
+		t.Errorf("unexpected replies %+v", replies)
+	}
+
+	reply.Set("content", "START")
+	if rec := postEvent(handler, reply); rec.Code != http.StatusForbidden {
+		t.Errorf("expected tampered callback to be rejected, got %d", rec.Code)
+	}
+	if len(replies) != 1 {
+		t.Errorf("unexpected replies %+v", replies)
+	}
+}